@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+/*
+<summary>
+
+	Get who is doing the request, at this order of priority
+	User api key => Master key => Form session user => Server token (only hash is stored)
+
+</summary>
+*/
+func GetAuditActor(r *http.Request) (actorType string, actor string) {
+	apikey := GetApiKey(r)
+	if len(apikey) > 0 {
+		authorization, err := models.GetApiAuthorization(apikey)
+		if err == nil {
+			return models.AuditActorApiKey, authorization.User.Username + " (" + authorization.Key.Prefix + ")"
+		}
+	}
+
+	masterkey := GetMasterKey(r)
+	if len(masterkey) > 0 && strings.EqualFold(masterkey, models.ENV.MasterKey()) {
+		return models.AuditActorMasterKey, "master"
+	}
+
+	user, err := models.GetFormUser(r)
+	if err == nil && user != nil {
+		return models.AuditActorUser, user.Username
+	}
+
+	return models.AuditActorToken, GetToken(r)
+}
+
+// Records an audit entry for the request actor, payload is kept only as digest
+func AuditRequest(r *http.Request, action string, target string, resource string, payload interface{}, details string) {
+	actorType, actor := GetAuditActor(r)
+
+	// api keys bound to a server does not require token
+	if actorType == models.AuditActorToken && len(actor) == 0 {
+		actor = target
+	}
+
+	models.Audit(&models.QpAuditEntry{
+		ActorType: actorType,
+		Actor:     actor,
+		Action:    action,
+		Target:    target,
+		Resource:  resource,
+		Digest:    models.GetAuditDigest(payload),
+		Details:   details,
+	})
+}
+
+// Records a sent or queued message, keeping only ids, type and payload digest
+func AuditSend(r *http.Request, server *models.QpWhatsappServer, msg *whatsapp.WhatsappMessage, id string, queued bool, payload interface{}) {
+	details := fmt.Sprintf("id: %s, type: %s", id, msg.Type)
+	if queued {
+		details += ", queued"
+	}
+
+	AuditRequest(r, models.AuditActionMessageSend, server.Token, msg.Chat.Id, payload, details)
+}
+
+// Records a failed send attempt, with the error cause
+func AuditSendFailed(r *http.Request, server *models.QpWhatsappServer, msg *whatsapp.WhatsappMessage, payload interface{}, err error) {
+	details := fmt.Sprintf("id: %s, type: %s, error: %s", msg.Id, msg.Type, err.Error())
+	AuditRequest(r, models.AuditActionMessageFailed, server.Token, msg.Chat.Id, payload, details)
+}
+
+// Records an action over an existing message (edit, revoke or react), chat is taken from cache when available
+func AuditMessage(r *http.Request, action string, server *models.QpWhatsappServer, messageid string, payload interface{}, details string) {
+	resource := messageid
+	if server.Handler != nil {
+		msg, err := server.Handler.GetById(messageid)
+		if err == nil && msg != nil && len(msg.Chat.Id) > 0 {
+			resource = msg.Chat.Id
+		}
+	}
+
+	details = fmt.Sprintf("id: %s%s", messageid, details)
+	AuditRequest(r, action, server.Token, resource, payload, details)
+}
@@ -94,6 +94,18 @@ func GetToken(r *http.Request) string {
 	return models.GetRequestParameter(r, "token")
 }
 
+/*
+<summary>
+
+	Get User Api Key From Http Request
+	Getting from PATH => QUERY => HEADER (X-QUEPASA-APIKEY)
+
+</summary>
+*/
+func GetApiKey(r *http.Request) string {
+	return models.GetRequestParameter(r, "apikey")
+}
+
 /*
 <summary>
 
@@ -189,6 +201,33 @@ func GetInReplyParameter(r *http.Request) string {
 	return models.GetRequestParameter(r, "inreply")
 }
 
+/*
+<summary>
+
+	Get Send At (schedule) From Http Request, RFC3339 or unix seconds
+	Getting from PATH => QUERY => FROM => HEADER
+
+</summary>
+*/
+func GetSendAtParameter(r *http.Request) (*time.Time, error) {
+	return models.ParseSendAt(models.GetRequestParameter(r, "sendat"))
+}
+
+/*
+<summary>
+
+	Get Idempotency Key for sends, from "Idempotency-Key" header or request id
+
+</summary>
+*/
+func GetIdempotencyKey(r *http.Request, request *models.QpSendRequest) string {
+	key := r.Header.Get(models.IdempotencyKeyHeader)
+	if len(key) == 0 {
+		key = request.Id
+	}
+	return key
+}
+
 /*
 <summary>
 
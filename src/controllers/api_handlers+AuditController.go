@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - AUDIT
+
+// default and max entries per page
+const (
+	AuditDefaultLimit uint = 50
+	AuditMaxLimit     uint = 500
+)
+
+// gets audit filter from url parameters
+func GetAuditFilter(r *http.Request) (filter *models.QpAuditFilter, err error) {
+	query := r.URL.Query()
+	filter = &models.QpAuditFilter{
+		ActorType: query.Get("actortype"),
+		Actor:     query.Get("actor"),
+		Action:    query.Get("action"),
+		Target:    models.GetAuditTokenHash(query.Get("target")),
+		Resource:  query.Get("resource"),
+		Limit:     AuditDefaultLimit,
+	}
+
+	// bot tokens are stored as hash
+	if filter.ActorType == models.AuditActorToken {
+		filter.Actor = models.GetAuditTokenHash(filter.Actor)
+	}
+
+	for name, destination := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if param := query.Get(name); len(param) > 0 {
+			value, err := time.Parse(time.RFC3339, param)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s, use RFC3339: %s", name, param)
+			}
+			*destination = &value
+		}
+	}
+
+	if param := query.Get("limit"); len(param) > 0 {
+		value, err := strconv.ParseUint(param, 10, 32)
+		if err != nil || value == 0 {
+			return nil, fmt.Errorf("invalid limit: %s", param)
+		}
+
+		filter.Limit = uint(value)
+		if filter.Limit > AuditMaxLimit {
+			filter.Limit = AuditMaxLimit
+		}
+	}
+
+	if param := query.Get("offset"); len(param) > 0 {
+		value, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset: %s", param)
+		}
+		filter.Offset = uint(value)
+	}
+
+	return
+}
+
+/*
+<summary>
+
+	Renders route GET "/audit"
+
+	Requires master key or an api key from an admin user with manage scope
+	Lists audit entries, newest first
+	Url parameters: ?actortype={token|masterkey|apikey|user}&actor={actor}&action={action or prefix, ex: server.}&target={server token or its hash}&resource={resource}&since={RFC3339}&until={RFC3339}&limit={default 50, max 500}&offset={offset}
+
+</summary>
+*/
+func AuditController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpAuditResponse{}
+
+	err := EnsureAdministrator(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	filter, err := GetAuditFilter(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	db := models.WhatsappService.DB.Audit
+	response.Total, err = db.Count(filter)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Entries, err = db.Find(filter)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Limit = filter.Limit
+	response.Offset = filter.Offset
+	RespondSuccess(w, response)
+}
+
+//endregion
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - AUTO RULES
+
+// gets the auto rules engine of the server from request
+func GetAutoRules(r *http.Request) (*models.QpAutoRules, error) {
+	server, err := GetServer(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if server.AutoRules == nil {
+		return nil, fmt.Errorf("auto rules not available for this server")
+	}
+
+	return server.AutoRules, nil
+}
+
+/*
+<summary>
+
+	Renders route GET|POST "/rules"
+
+	GET lists auto reply and keyword rules of this server, ordered by priority
+	POST creates a rule, first enabled matching rule is applied on received messages
+	Body parameters: {"name": "{name}", "priority": 0, "disabled": false, "conditions": {"chats": ["{glob}"], "types": ["text"], "text": "{regex}", "days": [1,2,3,4,5], "start": "09:00", "end": "18:00", "outside": true, "timezone": "{iana}", "firstcontact": true}, "action": {"type": "reply|forward|webhook", "text": "{template}", "url": "{media url}", "inreply": true, "chatid": "{forward to}", "webhook": "{url}"}}
+
+</summary>
+*/
+func AutoRulesController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpAutoRulesResponse{}
+
+	rules, err := GetAutoRules(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		rule := &models.QpAutoRule{}
+		err = decodeJsonBody(r, rule)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		// always generated
+		rule.Id = ""
+
+		err = rules.Save(rule)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess(fmt.Sprintf("created with success, id: %s", rule.Id))
+	}
+
+	response.Rules = rules.GetRules()
+	response.Total = len(response.Rules)
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route GET|PUT|DELETE "/rules/{ruleid}"
+
+	GET gets a rule
+	PUT replaces a rule, same body as POST "/rules"
+	DELETE removes a rule
+
+</summary>
+*/
+func AutoRuleController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpAutoRuleResponse{}
+
+	rules, err := GetAutoRules(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	id := models.GetRequestParameter(r, "ruleid")
+	if len(id) == 0 {
+		err = fmt.Errorf("rule id missing")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		rule := &models.QpAutoRule{}
+		err = decodeJsonBody(r, rule)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		rule.Id = id
+		err = rules.Save(rule)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Rule = rule
+		response.ParseSuccess("updated with success")
+	case http.MethodDelete:
+		err = rules.Remove(id)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess("deleted with success")
+	default:
+		response.Rule, err = rules.GetById(id)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	RespondSuccess(w, response)
+}
+
+//endregion
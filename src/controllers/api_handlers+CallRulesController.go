@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - CALL RULES
+
+/*
+<summary>
+
+	Renders route GET|PUT|DELETE "/calls/rules"
+
+	GET gets incoming call rules of this server
+	PUT replaces all rules, first matching rule is applied on call offers
+	Body parameters: {"rules": [{"action": "reject|reply", "reply": "{text}", "days": [1,2,3,4,5], "start": "09:00", "end": "18:00", "outside": true, "timezone": "{iana}", "chats": ["{glob}"], "video": false}]}
+	DELETE removes all rules
+
+</summary>
+*/
+func CallRulesController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpCallRulesResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		request := &models.QpCallRules{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		err = server.SetCallRules(request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess(fmt.Sprintf("updated with success, %v rule(s)", len(request.Rules)))
+	case http.MethodDelete:
+		err = server.SetCallRules(nil)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess("deleted with success")
+	}
+
+	response.CallRules = server.CallRules
+	RespondSuccess(w, response)
+}
+
+//endregion
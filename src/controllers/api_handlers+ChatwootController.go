@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - CHATWOOT
+
+/*
+<summary>
+
+	Renders route GET|PUT|DELETE "/chatwoot"
+
+	GET gets chatwoot channel settings of this server, without access token
+	PUT sets settings, received messages are mirrored to the api inbox
+	Body parameters: {"url": "{chatwoot url}", "account": 1, "inbox": 2, "token": "{api access token}", "groups": false, "sign": false, "disabled": false}
+	* token may be omitted on updates, keeping the current one
+	DELETE removes the integration
+
+	* set the inbox webhook url as "{quepasa url}/chatwoot/webhook/{secret}" to receive agent replies,
+	  secret is generated on first PUT and returned as "webhook" path
+
+</summary>
+*/
+func ChatwootController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpChatwootResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		request := &models.QpChatwootConfig{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		err = server.SetChatwoot(request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess(fmt.Sprintf("updated with success, inbox: %v", request.Inbox))
+	case http.MethodDelete:
+		err = server.SetChatwoot(nil)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess("deleted with success")
+	}
+
+	chatwoot := server.GetChatwoot()
+	response.Chatwoot = chatwoot.Redacted()
+	response.Webhook = chatwoot.GetWebhookPath()
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/chatwoot/webhook/{secret}"
+
+	Receives chatwoot inbox webhook events, public agent replies are sent to the whatsapp chat
+	* private notes and messages mirrored by quepasa are ignored
+
+</summary>
+*/
+func ChatwootWebhookController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := models.GetServerFromChatwootSecret(chi.URLParam(r, "secret"))
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if server.ChatwootHandler == nil {
+		err = fmt.Errorf("chatwoot not available for this server")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	payload := &models.QpChatwootWebhookPayload{}
+	err = decodeJsonBody(r, payload)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	sent, err := server.ChatwootHandler.Receive(payload)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(fmt.Sprintf("%v message(s) sent", sent))
+	RespondSuccess(w, response)
+}
+
+//endregion
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - EVENTS
+
+// interval between keep alive comments, avoiding proxies to close idle streams
+const EventsKeepAliveInterval = 25 * time.Second
+
+// gets last received event id, from header or "lasteventid" url parameter (browsers can not set headers on EventSource)
+func GetLastEventId(r *http.Request) string {
+	lastid := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if len(lastid) == 0 {
+		lastid = strings.TrimSpace(models.GetRequestParameter(r, "lasteventid"))
+	}
+	return lastid
+}
+
+/*
+<summary>
+
+	Renders route GET "/events" and "/events/{token}"
+
+	Server sent events stream of a token:
+		* message: cached messages, event id is the message id
+		* receipt: read receipts
+		* presence: presence updates
+		* status: delivery status updates of cached messages
+		* state: connection state transitions
+		* lagged: events discarded due to slow reading, with the dropped count
+
+	With Last-Event-ID, replays cached messages received after that message
+
+</summary>
+*/
+func EventsController(w http.ResponseWriter, r *http.Request) {
+
+	server, err := GetServer(r)
+	if err != nil {
+		response := &models.QpResponse{}
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+
+	// long running stream, disabling server write timeout for this request
+	err = controller.SetWriteDeadline(time.Time{})
+	if err != nil {
+		logentry := server.GetLogger()
+		logentry.Warnf("error on disabling write deadline for events stream: %s", err.Error())
+	}
+
+	// subscribing before replay, avoiding lost messages between them
+	subscriber := models.EventHub.Subscribe(server.Token)
+	defer models.EventHub.Unsubscribe(subscriber)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// reconnection delay hint for browsers
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	replayed := map[string]bool{}
+	if lastid := GetLastEventId(r); len(lastid) > 0 {
+		messages, err := server.GetMessagesAfter(lastid)
+		if err != nil {
+			fmt.Fprintf(w, ": last event id not found on cache: %s\n\n", lastid)
+		}
+
+		for _, message := range messages {
+			replayed[message.Id] = true
+			err = WriteEvent(w, &models.QpEvent{Id: message.Id, Type: models.EventTypeMessage, Data: message})
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	err = controller.Flush()
+	if err != nil {
+		return
+	}
+
+	keepalive := time.NewTicker(EventsKeepAliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			_, err = fmt.Fprint(w, ": keepalive\n\n")
+		case event := <-subscriber.Events:
+
+			// already sent on replay
+			if len(event.Id) > 0 && replayed[event.Id] {
+				delete(replayed, event.Id)
+				continue
+			}
+
+			err = WriteEvent(w, event)
+		case <-subscriber.Lagged:
+			if lagged := subscriber.TakeLagged(); lagged != nil {
+				err = WriteEvent(w, lagged)
+			}
+		}
+
+		if err == nil {
+			err = controller.Flush()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// writes an event on server sent events format
+func WriteEvent(w http.ResponseWriter, event *models.QpEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	var builder strings.Builder
+	if len(event.Id) > 0 {
+		builder.WriteString("id: " + event.Id + "\n")
+	}
+	builder.WriteString("event: " + event.Type + "\n")
+	builder.WriteString("data: " + string(data) + "\n\n")
+
+	_, err = w.Write([]byte(builder.String()))
+	return err
+}
+
+//endregion
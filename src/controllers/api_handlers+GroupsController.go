@@ -0,0 +1,472 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - GROUPS
+
+// gets group id from request, must be a formatted (@g.us) group id
+func GetGroupId(r *http.Request) (chatId string, err error) {
+	chatId = models.GetChatId(r)
+	if len(chatId) == 0 {
+		err = fmt.Errorf("chat id missing")
+		return
+	}
+
+	if !strings.HasSuffix(chatId, "@g.us") {
+		err = fmt.Errorf("chatId must be a valid and formatted (@g.us) group id")
+	}
+	return
+}
+
+// decodes json body into request, if any
+func decodeJsonBody(r *http.Request, request interface{}) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("invalid json body: %s", err.Error())
+	}
+	return nil
+}
+
+/*
+<summary>
+
+	Renders route GET|POST "/groups"
+
+	GET lists joined groups with participants
+	POST creates a new group
+	Body parameters: {"name": "{name}", "participants": ["{phone or wid}"]}
+
+</summary>
+*/
+func GroupsController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	server, err := GetServer(r)
+	if err != nil {
+		response := &models.QpResponse{}
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetGroupManager()
+	if err != nil {
+		response := &models.QpResponse{}
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		response := &models.QpGroupResponse{}
+
+		request := &models.QpGroupCreateRequest{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		if len(strings.TrimSpace(request.Name)) == 0 {
+			err = fmt.Errorf("group name missing")
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		group, err := manager.CreateGroup(request.Name, request.Participants)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		logentry := server.GetLogger()
+		logentry.Infof("group created: %s", group.Id)
+
+		response.Group = group
+		response.ParseSuccess("created with success")
+		RespondSuccess(w, response)
+		return
+	default:
+		response := &models.QpGroupsResponse{}
+
+		groups, err := manager.GetJoinedGroups()
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Total = len(groups)
+		response.Groups = groups
+		RespondSuccess(w, response)
+		return
+	}
+}
+
+/*
+<summary>
+
+	Renders route GET|PUT "/groups/{chatid}"
+
+	GET gets group metadata with participants
+	PUT changes group settings, only informed ones
+	Body parameters: {"name": "{subject}", "topic": "{description}", "announce": true, "locked": true}
+
+</summary>
+*/
+func GroupController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpGroupResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	groupId, err := GetGroupId(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetGroupManager()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		request := &models.QpGroupUpdateRequest{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		if request.Name != nil {
+			err = manager.SetGroupName(groupId, *request.Name)
+		}
+
+		if err == nil && request.Topic != nil {
+			err = manager.SetGroupTopic(groupId, *request.Topic)
+		}
+
+		if err == nil && request.Announce != nil {
+			err = manager.SetGroupAnnounce(groupId, *request.Announce)
+		}
+
+		if err == nil && request.Locked != nil {
+			err = manager.SetGroupLocked(groupId, *request.Locked)
+		}
+
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		logentry := server.GetLogger()
+		logentry.Infof("group updated: %s", groupId)
+	}
+
+	group, err := manager.GetGroupInfo(groupId)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Group = group
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/groups/{chatid}/participants"
+
+	Adds, removes, promotes or demotes participants
+	Body parameters: {"action": "add|remove|promote|demote", "participants": ["{phone or wid}"]}
+
+</summary>
+*/
+func GroupParticipantsController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpGroupParticipantsResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	groupId, err := GetGroupId(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpGroupParticipantsRequest{}
+	err = decodeJsonBody(r, request)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	action := whatsapp.GetGroupParticipantActionFromString(request.Action)
+	if action == whatsapp.UnknownGroupParticipantAction {
+		err = fmt.Errorf("invalid action: {%s}, try {add,remove,promote,demote}", request.Action)
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.Participants) == 0 {
+		err = fmt.Errorf("participants missing")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetGroupManager()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	participants, err := manager.UpdateGroupParticipants(groupId, request.Participants, action)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	logentry := server.GetLogger()
+	logentry.Infof("group participants updated: %s, action: %s, items: %v", groupId, action, len(participants))
+
+	response.Participants = participants
+	response.ParseSuccess(fmt.Sprintf("%s with success", action))
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route PUT "/groups/{chatid}/picture"
+
+	Changes group picture, binary jpeg image on body
+
+</summary>
+*/
+func GroupPictureController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpPictureResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	groupId, err := GetGroupId(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(content) == 0 {
+		err = fmt.Errorf("picture content missing, send a jpeg image on body")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetGroupManager()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	pictureId, err := manager.SetGroupPhoto(groupId, content)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Info = &whatsapp.WhatsappProfilePicture{
+		Id:     pictureId,
+		ChatId: groupId,
+		Wid:    server.GetWId(),
+	}
+	response.ParseSuccess("picture changed with success")
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/groups/{chatid}/invite/revoke"
+
+	Revokes current invite link, returning the new one
+
+</summary>
+*/
+func GroupInviteRevokeController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpInviteResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	groupId, err := GetGroupId(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetGroupManager()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	url, err := manager.RevokeInvite(groupId)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Url = url
+	response.ParseSuccess("invite revoked with success")
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/groups/join"
+
+	Joins a group via invite code or link
+	Body parameters: {"code": "{code or link}"}
+	Url parameters: ?code={code}
+
+</summary>
+*/
+func GroupJoinController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpGroupResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpGroupJoinRequest{}
+	err = decodeJsonBody(r, request)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.Code) == 0 {
+		request.Code = models.GetRequestParameter(r, "code")
+	}
+
+	if len(request.Code) == 0 {
+		err = fmt.Errorf("invite code missing")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetGroupManager()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	groupId, err := manager.JoinGroup(request.Code)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	logentry := server.GetLogger()
+	logentry.Infof("group joined: %s", groupId)
+
+	response.Group = &whatsapp.WhatsappGroup{Id: groupId}
+	if group, err := manager.GetGroupInfo(groupId); err == nil {
+		response.Group = group
+	}
+
+	response.ParseSuccess("joined with success")
+	RespondSuccess(w, response)
+}
+
+//endregion
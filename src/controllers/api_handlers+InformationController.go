@@ -41,6 +41,15 @@ func InformationGetRequest(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response.ParseSuccess(server)
+	RespondInformation(w, r, response)
+}
+
+// api keys are limited by scopes, bot token would bypass them
+func RespondInformation(w http.ResponseWriter, r *http.Request, response *models.QpInfoResponse) {
+	if len(GetApiKey(r)) > 0 {
+		response.RedactToken()
+	}
+
 	RespondSuccess(w, response)
 }
 
@@ -149,6 +158,24 @@ func InformationPatchRequest(w http.ResponseWriter, r *http.Request) {
 
 	//#endregion
 
+	if request.SendQueue != nil {
+		settings := request.SendQueue
+		err = settings.Validate()
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		if settings.IsEmpty() {
+			settings = nil
+		}
+
+		server.SendQueueSettings = settings
+		content, _ := json.Marshal(settings)
+		update += fmt.Sprintf("sendqueue to: %s; ", content)
+	}
+
 	if len(update) > 0 {
 		err = server.Save("patching info")
 		if err != nil {
@@ -161,11 +188,13 @@ func InformationPatchRequest(w http.ResponseWriter, r *http.Request) {
 		logentry := server.GetLogger()
 		logentry.Info(info)
 
+		AuditRequest(r, models.AuditActionServerUpdate, server.Token, server.Wid, body, update)
+
 		response.PatchSuccess(server, "server updated")
-		RespondSuccess(w, response)
+		RespondInformation(w, r, response)
 	} else {
 		response.PatchSuccess(server, "no update required")
-		RespondSuccess(w, response)
+		RespondInformation(w, r, response)
 	}
 }
 
@@ -192,6 +221,8 @@ func InformationDeleteRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	AuditRequest(r, models.AuditActionServerDelete, server.Token, server.Wid, nil, "server deleted")
+
 	response.ParseSuccess("server deleted")
 	RespondSuccess(w, response)
 }
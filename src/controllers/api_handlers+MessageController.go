@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -88,9 +89,161 @@ func RevokeController(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		details := ""
+		if GetMessageIdAsPrefix(r) {
+			details = ", prefix"
+		}
+		AuditMessage(r, models.AuditActionMessageRevoke, server, messageid, messageid, details)
+
 		response.ParseSuccess("revoked with success")
 		RespondSuccess(w, response)
 	}
 }
 
+/*
+<summary>
+
+	Renders route PUT "/message/{messageid}"
+
+	Edits the text of a message sent by this server, edited message is also dispatched to webhooks
+	Body parameters: {"text": "{text}"}
+	Url parameters: ?messageid={messageid}&text={text}
+	Header parameters: X-QUEPASA-MESSAGEID = {messageid}, X-QUEPASA-TEXT = {text}
+
+</summary>
+*/
+func EditController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpMessageResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpEditRequest{}
+	if r.ContentLength > 0 {
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			err = fmt.Errorf("invalid json body: %s", err.Error())
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	if len(request.MessageId) == 0 {
+		request.MessageId = GetMessageId(r)
+	}
+
+	if len(request.Text) == 0 {
+		request.Text = GetTextParameter(r)
+	}
+
+	if len(request.MessageId) == 0 {
+		err = fmt.Errorf("empty message id")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.Text) == 0 {
+		err = fmt.Errorf("empty text, use revoke to remove a message")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	msg, err := server.Edit(request.MessageId, request.Text)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	AuditMessage(r, models.AuditActionMessageEdit, server, request.MessageId, request, "")
+
+	response.ParseSuccess("edited with success")
+	response.Message = msg
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/react"
+
+	Sends an emoji reaction to a cached message, empty emoji removes a previous reaction
+	Body parameters: {"messageid": "{messageid}", "emoji": "{emoji}"}
+	Url parameters: ?messageid={messageid}&emoji={emoji}
+	Header parameters: X-QUEPASA-MESSAGEID = {messageid}, X-QUEPASA-EMOJI = {emoji}
+
+</summary>
+*/
+func ReactController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpReactRequest{}
+	if r.ContentLength > 0 {
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			err = fmt.Errorf("invalid json body: %s", err.Error())
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	if len(request.MessageId) == 0 {
+		request.MessageId = GetMessageId(r)
+	}
+
+	if len(request.Emoji) == 0 {
+		request.Emoji = models.GetRequestParameter(r, "emoji")
+	}
+
+	if len(request.MessageId) == 0 {
+		err = fmt.Errorf("empty message id")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = server.React(request.MessageId, request.Emoji)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	details := ""
+	if len(request.Emoji) == 0 {
+		details = ", removed"
+	}
+	AuditMessage(r, models.AuditActionMessageReact, server, request.MessageId, request, details)
+
+	if len(request.Emoji) > 0 {
+		response.ParseSuccess("reacted with success")
+	} else {
+		response.ParseSuccess("reaction removed with success")
+	}
+	RespondSuccess(w, response)
+}
+
 //endregion
@@ -0,0 +1,302 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	metrics "github.com/nocodeleaks/quepasa/metrics"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - NEWSLETTERS
+
+// gets newsletter id from request, must be a formatted (@newsletter) id
+func GetNewsletterId(r *http.Request) (chatId string, err error) {
+	chatId = models.GetChatId(r)
+	if len(chatId) == 0 {
+		err = fmt.Errorf("chat id missing")
+		return
+	}
+
+	if !strings.HasSuffix(chatId, whatsapp.WhatsappNewsletterSuffix) {
+		err = fmt.Errorf("chatId must be a valid and formatted (@newsletter) newsletter id")
+	}
+	return
+}
+
+/*
+<summary>
+
+	Renders route GET "/newsletters"
+
+	Lists followed and owned newsletters (channels)
+
+</summary>
+*/
+func NewslettersController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpNewslettersResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetNewsletterManager()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	newsletters, err := manager.GetSubscribedNewsletters()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Total = len(newsletters)
+	response.Newsletters = newsletters
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/newsletters/follow"
+
+	Follows a newsletter (channel), posts are emitted to webhooks when NEWSLETTERS is enabled
+	Body parameters: {"id": "{newsletter id, invite code or link}"}
+	Url parameters: ?id={id}
+
+</summary>
+*/
+func NewsletterFollowController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpNewsletterResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpNewsletterFollowRequest{}
+	err = decodeJsonBody(r, request)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.Id) == 0 {
+		request.Id = models.GetRequestParameter(r, "id")
+	}
+
+	if len(strings.TrimSpace(request.Id)) == 0 {
+		err = fmt.Errorf("newsletter id or invite missing")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetNewsletterManager()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	newsletter, err := manager.FollowNewsletter(request.Id)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Newsletter = newsletter
+	response.ParseSuccess(fmt.Sprintf("following newsletter %s", newsletter.Id))
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route GET|DELETE "/newsletters/{chatid}"
+
+	GET gets newsletter metadata, including viewer role
+	DELETE unfollows the newsletter
+
+</summary>
+*/
+func NewsletterController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpNewsletterResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	chatId, err := GetNewsletterId(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetNewsletterManager()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		err = manager.UnfollowNewsletter(chatId)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.ParseSuccess(fmt.Sprintf("unfollowed newsletter %s", chatId))
+		RespondSuccess(w, response)
+		return
+	}
+
+	newsletter, err := manager.GetNewsletterInfo(chatId)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Newsletter = newsletter
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route GET|POST "/newsletters/{chatid}/messages"
+
+	GET fetches recent posts, ids are server ids
+	Url parameters: ?count={count, default 20}&before={server id}
+
+	POST publishes a post, only on owned or administered newsletters
+	Body parameters: same as "/send", {"text": "{text}", "url": "{url}", "content": "{base64}", "filename": "{filename}"}
+
+</summary>
+*/
+func NewsletterMessagesController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpNewsletterMessagesResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	chatId, err := GetNewsletterId(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	manager, err := server.GetNewsletterManager()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		newsletter, err := manager.GetNewsletterInfo(chatId)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		if !newsletter.CanPost() {
+			metrics.MessageSendErrors.Inc()
+			err = fmt.Errorf("only owners and admins can post on newsletter: %s, role: %s", chatId, newsletter.Role)
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		request := &models.QpSendAnyRequest{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			metrics.MessageSendErrors.Inc()
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		request.ChatId = chatId
+		SendAnyContent(w, r, request, server)
+		return
+	}
+
+	count := 20
+	if param := models.GetRequestParameter(r, "count"); len(param) > 0 {
+		count, err = strconv.Atoi(param)
+		if err != nil || count <= 0 {
+			err = fmt.Errorf("invalid count: %s", param)
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	var before int
+	if param := models.GetRequestParameter(r, "before"); len(param) > 0 {
+		before, err = strconv.Atoi(param)
+		if err != nil {
+			err = fmt.Errorf("invalid before server id: %s", param)
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	messages, err := manager.GetNewsletterMessages(chatId, count, before)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Total = len(messages)
+	response.Messages = messages
+	RespondSuccess(w, response)
+}
+
+//endregion
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - PRESENCE
+
+// gets presence request from body, then parameters
+func GetPresenceRequest(r *http.Request) (request *models.QpPresenceRequest, err error) {
+	request = &models.QpPresenceRequest{}
+	err = decodeJsonBody(r, request)
+	if err != nil {
+		return
+	}
+
+	if len(request.ChatId) == 0 {
+		request.ChatId = models.GetChatId(r)
+	}
+
+	if len(request.Presence) == 0 {
+		request.Presence = models.GetRequestParameter(r, "presence")
+	}
+
+	request.Presence = strings.ToLower(strings.TrimSpace(request.Presence))
+	return
+}
+
+/*
+<summary>
+
+	Renders route POST "/presence"
+
+	Sets global presence for this server, until restart
+	Body parameters: {"presence": "available|unavailable"}
+	Url parameters: ?presence={presence}
+
+</summary>
+*/
+func PresenceController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request, err := GetPresenceRequest(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	var available bool
+	switch request.Presence {
+	case "available":
+		available = true
+	case "unavailable":
+		available = false
+	default:
+		err = fmt.Errorf("invalid presence: {%s}, try {available,unavailable}", request.Presence)
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = server.SetPresence(available)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(fmt.Sprintf("presence set to %s", request.Presence))
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/chatpresence"
+
+	Sends chat presence (typing indicator) to a chat
+	Body parameters: {"chatid": "{chatid}", "presence": "composing|recording|paused"}
+	Url parameters: ?chatid={chatid}&presence={presence}
+
+</summary>
+*/
+func ChatPresenceController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request, err := GetPresenceRequest(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	chatId, err := whatsapp.FormatEndpoint(request.ChatId)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	presence := whatsapp.GetChatPresenceFromString(request.Presence)
+	if presence == whatsapp.WhatsappChatPresencePaused && request.Presence != presence.String() {
+		err = fmt.Errorf("invalid chat presence: {%s}, try {composing,recording,paused}", request.Presence)
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = server.SendChatPresence(chatId, presence)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(fmt.Sprintf("chat presence %s sent to %s", presence, chatId))
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/presence/subscribe"
+
+	Subscribes to a contact presence, updates are emitted to webhooks and signalr as "presence" messages
+	Body parameters: {"chatid": "{chatid}"}
+	Url parameters: ?chatid={chatid}
+
+</summary>
+*/
+func PresenceSubscribeController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request, err := GetPresenceRequest(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.ChatId) == 0 {
+		err = fmt.Errorf("chat id missing")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = server.SubscribePresence(request.ChatId)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(fmt.Sprintf("subscribed to presence of %s", request.ChatId))
+	RespondSuccess(w, response)
+}
+
+//endregion
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - READ
+
+/*
+<summary>
+
+	Renders route POST "/read"
+
+	Marks cached received messages as read, sending read receipts to the sender
+	Body parameters: {"messageids": ["{messageid}"], "chatid": "{chatid}"}
+	Url parameters: ?messageid={messageid}&chatid={chatid}
+	* chatid marks every cached received message of the chat up to now
+
+</summary>
+*/
+func ReadController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpReadResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpReadRequest{}
+	err = decodeJsonBody(r, request)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.MessageIds) == 0 {
+		if messageId := GetMessageId(r); len(messageId) > 0 {
+			request.MessageIds = append(request.MessageIds, messageId)
+		}
+	}
+
+	if len(request.ChatId) == 0 {
+		request.ChatId = models.GetChatId(r)
+	}
+
+	if len(request.ChatId) > 0 {
+		request.ChatId, err = whatsapp.FormatEndpoint(request.ChatId)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	if len(request.MessageIds) == 0 && len(request.ChatId) == 0 {
+		err = fmt.Errorf("message ids or chat id missing")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	ids, err := server.MarkRead(request.MessageIds, request.ChatId)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Total = len(ids)
+	response.Ids = ids
+	response.ParseSuccess(fmt.Sprintf("marked as read %v message(s)", len(ids)))
+	RespondSuccess(w, response)
+}
+
+//endregion
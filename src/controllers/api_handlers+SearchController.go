@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - SEARCH
+
+// default and max messages per page
+const (
+	SearchDefaultLimit uint = 50
+	SearchMaxLimit     uint = 500
+)
+
+// gets messages search filter from url parameters
+func GetMessageSearchFilter(r *http.Request) (filter *models.QpMessageSearchFilter, err error) {
+	query := r.URL.Query()
+	filter = &models.QpMessageSearchFilter{
+		Query: strings.TrimSpace(query.Get("q")),
+		Limit: SearchDefaultLimit,
+	}
+
+	if len(filter.Query) == 0 {
+		return nil, fmt.Errorf("missing search query (q)")
+	}
+
+	if param := query.Get("chatid"); len(param) > 0 {
+		filter.ChatId, err = whatsapp.FormatEndpoint(param)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if param := strings.ToLower(query.Get("type")); len(param) > 0 {
+		if whatsapp.GetMessageTypeFromString(param) == whatsapp.UnknownMessageType && param != whatsapp.UnknownMessageType.String() {
+			return nil, fmt.Errorf("invalid message type: %s", param)
+		}
+		filter.Type = param
+	}
+
+	for name, destination := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if param := query.Get(name); len(param) > 0 {
+			value, err := time.Parse(time.RFC3339, param)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s, use RFC3339: %s", name, param)
+			}
+			*destination = &value
+		}
+	}
+
+	if param := query.Get("limit"); len(param) > 0 {
+		value, err := strconv.ParseUint(param, 10, 32)
+		if err != nil || value == 0 {
+			return nil, fmt.Errorf("invalid limit: %s", param)
+		}
+
+		filter.Limit = uint(value)
+		if filter.Limit > SearchMaxLimit {
+			filter.Limit = SearchMaxLimit
+		}
+	}
+
+	if param := query.Get("offset"); len(param) > 0 {
+		value, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset: %s", param)
+		}
+		filter.Offset = uint(value)
+	}
+
+	return
+}
+
+/*
+<summary>
+
+	Renders route GET "/search"
+
+	Full text search over messages text, captions, filenames and contact names, newest first
+	Uses the durable message store when enabled, otherwise cached messages
+	Url parameters: ?q={terms, all required}&chatid={chat id}&type={message type}&from={RFC3339}&to={RFC3339}&limit={default 50, max 500}&offset={offset}
+
+</summary>
+*/
+func SearchController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpSearchResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	filter, err := GetMessageSearchFilter(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Messages, response.Total, err = server.Handler.Search(filter)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Limit = filter.Limit
+	response.Offset = filter.Offset
+	RespondSuccess(w, response)
+}
+
+//endregion
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -70,6 +71,14 @@ func SendAnyWithServer(w http.ResponseWriter, r *http.Request, server *models.Qp
 		}
 	*/
 
+	SendAnyContent(w, r, request, server)
+}
+
+// Gets attachment content from url or base64 and sends, chat id already validated
+func SendAnyContent(w http.ResponseWriter, r *http.Request, request *models.QpSendAnyRequest, server *models.QpWhatsappServer) {
+	response := &models.QpSendResponse{}
+	var err error
+
 	if len(request.Url) == 0 && r.URL.Query().Has("url") {
 		request.Url = r.URL.Query().Get("url")
 	}
@@ -126,7 +135,18 @@ func SendRequest(w http.ResponseWriter, r *http.Request, request *models.QpSendR
 		}
 	}
 
-	if att.Attach == nil && len(request.Text) == 0 {
+	// if not set, try to recover "sendat"
+	if request.SendAt == nil {
+		request.SendAt, err = GetSendAtParameter(r)
+		if err != nil {
+			metrics.MessageSendErrors.Inc()
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	if att.Attach == nil && len(request.Text) == 0 && !request.IsInteractive() {
 		metrics.MessageSendErrors.Inc()
 		err = fmt.Errorf("text not found, do not send empty messages")
 		response.ParseError(err)
@@ -140,11 +160,65 @@ func SendRequest(w http.ResponseWriter, r *http.Request, request *models.QpSendR
 	}
 
 	response.Debug = append(response.Debug, att.Debug...)
-	Send(server, response, request, w, att.Attach)
+
+	// idempotent sends, same key (header or id) returns the original response
+	key := GetIdempotencyKey(r, request)
+	if len(key) > 0 {
+		previous, err := models.IdempotencyReserve(server.Token, key)
+		if err != nil {
+			response.ParseError(err)
+			code := uint(0)
+			if err == models.ErrIdempotencyInProgress {
+				code = http.StatusConflict
+			}
+			RespondInterfaceCode(w, response, code)
+			return
+		}
+
+		if previous != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(models.IdempotencyReplayedHeader, "true")
+			w.WriteHeader(http.StatusOK)
+			w.Write(previous)
+			return
+		}
+
+		recorder := &IdempotentResponseWriter{ResponseWriter: w}
+		defer func() {
+			err := models.IdempotencyFinish(server.Token, key, recorder.Code == http.StatusOK, recorder.Body.Bytes())
+			if err != nil {
+				server.GetLogger().Warnf("error on finishing idempotency key: %s, cause: %s", key, err.Error())
+			}
+		}()
+		w = recorder
+	}
+
+	Send(server, response, request, w, r, att.Attach)
+}
+
+// Response writer that keeps status code and body, used to save idempotent responses
+type IdempotentResponseWriter struct {
+	http.ResponseWriter
+	Code int
+	Body bytes.Buffer
+}
+
+func (source *IdempotentResponseWriter) WriteHeader(code int) {
+	source.Code = code
+	source.ResponseWriter.WriteHeader(code)
+}
+
+func (source *IdempotentResponseWriter) Write(content []byte) (int, error) {
+	if source.Code == 0 {
+		source.Code = http.StatusOK
+	}
+
+	source.Body.Write(content)
+	return source.ResponseWriter.Write(content)
 }
 
 // finally sends to the whatsapp server
-func Send(server *models.QpWhatsappServer, response *models.QpSendResponse, request *models.QpSendRequest, w http.ResponseWriter, attach *whatsapp.WhatsappAttachment) {
+func Send(server *models.QpWhatsappServer, response *models.QpSendResponse, request *models.QpSendRequest, w http.ResponseWriter, r *http.Request, attach *whatsapp.WhatsappAttachment) {
 	waMsg, err := request.ToWhatsappMessage()
 	if err != nil {
 		metrics.MessageSendErrors.Inc()
@@ -159,8 +233,8 @@ func Send(server *models.QpWhatsappServer, response *models.QpSendResponse, requ
 		waMsg.Attachment = attach
 		waMsg.Type = whatsapp.GetMessageType(attach)
 		logentry.Debugf("send attachment of type: %v, mime: %s, length: %v, filename: %s", waMsg.Type, attach.Mimetype, attach.FileLength, attach.FileName)
-	} else {
-		// test for poll, already set from ToWhatsappMessage
+	} else if !request.IsInteractive() {
+		// poll, list and buttons types are already set from ToWhatsappMessage
 		waMsg.Type = whatsapp.TextMessageType
 	}
 
@@ -174,25 +248,28 @@ func Send(server *models.QpWhatsappServer, response *models.QpSendResponse, requ
 		// *** implement an error here if not found any knowing type
 	}
 
-	// Checking for ready state
-	status := server.GetStatus()
-	if status != whatsapp.Ready {
-		err = &ApiServerNotReadyException{Wid: server.GetWId(), Status: status}
-		response.ParseError(err)
-		RespondInterfaceCode(w, response, http.StatusServiceUnavailable)
-		return
+	// Checking for ready state, queued messages waits for it
+	if !server.ShouldEnqueue(request.SendAt) {
+		status := server.GetStatus()
+		if status != whatsapp.Ready {
+			err = &ApiServerNotReadyException{Wid: server.GetWId(), Status: status}
+			AuditSendFailed(r, server, waMsg, request, err)
+			response.ParseError(err)
+			RespondInterfaceCode(w, response, http.StatusServiceUnavailable)
+			return
+		}
 	}
 
-	sendResponse, err := server.SendMessage(waMsg)
+	sendResponse, queued, err := server.SendMessageOrEnqueue(waMsg, request.SendAt)
 	if err != nil {
 		metrics.MessageSendErrors.Inc()
+		AuditSendFailed(r, server, waMsg, request, err)
 		response.ParseError(err)
 		RespondInterface(w, response)
 		return
 	}
 
-	// success
-	metrics.MessagesSent.Inc()
+	AuditSend(r, server, waMsg, sendResponse.GetId(), queued, request)
 
 	result := &models.QpSendResponseMessage{}
 	result.Wid = server.GetWId()
@@ -200,6 +277,16 @@ func Send(server *models.QpWhatsappServer, response *models.QpSendResponse, requ
 	result.ChatId = waMsg.Chat.Id
 	result.TrackId = waMsg.TrackId
 
+	if queued {
+		result.SendAt = request.SendAt
+		response.ParseQueued(result)
+		RespondInterface(w, response)
+		return
+	}
+
+	// success
+	metrics.MessagesSent.Inc()
+
 	response.ParseSuccess(result)
 	RespondInterface(w, response)
 }
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - SEND QUEUE
+
+/*
+<summary>
+
+	Renders route GET|DELETE "/sendqueue"
+
+	GET lists queued and failed messages, with attempts and last error of retried ones, DELETE cancels (removes) them
+	Optional filter for DELETE, empty for all
+	Url parameters: ?id={messageid}
+	Header parameters: X-QUEPASA-ID = {messageid}
+
+</summary>
+*/
+func SendQueueController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpSendQueueResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if server.SendQueue == nil {
+		err = fmt.Errorf("send queue not attached")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		id := models.GetRequestParameter(r, "id")
+		affected, err := server.SendQueue.Cancel(id)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Affected = affected
+		response.ParseSuccess("canceled with success")
+		RespondSuccess(w, response)
+		if affected > 0 {
+			logentry := server.GetLogger()
+			logentry.Infof("canceling queued messages id=%s, items affected: %v", id, affected)
+		}
+		return
+	default:
+		items, err := server.SendQueue.Items()
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Total = len(items)
+		response.Items = items
+		response.ParseSuccess("getting queued messages")
+		RespondSuccess(w, response)
+		return
+	}
+}
+
+//endregion
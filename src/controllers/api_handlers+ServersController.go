@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - SERVERS
+
+/*
+<summary>
+
+	Renders route GET|POST "/servers"
+
+	Requires master key or an api key from an admin user with manage scope
+	GET lists all servers with live status
+	POST creates and persists a server awaiting pairing, pair it with "/scan" or "/paircode" using the returned token
+	* informed tokens must have 8 to 100 letters, digits, dots, underscores or hyphens
+	Body parameters: {"token": "{optional}", "user": "{optional owner}", "devel": false, "groups": 1, "broadcasts": -1, "readreceipts": 0, "calls": 0}
+
+</summary>
+*/
+func ServersController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	err := EnsureAdministrator(r)
+	if err != nil {
+		response := &models.QpServersResponse{}
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		response := &models.QpServerResponse{}
+
+		request := &models.QpServerCreateRequest{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		if len(request.User) > 0 {
+			_, err = models.WhatsappService.DB.Users.Find(request.User)
+			if err != nil {
+				err = fmt.Errorf("user not found: %s", request.User)
+				response.ParseError(err)
+				RespondInterface(w, response)
+				return
+			}
+		}
+
+		server, err := models.WhatsappService.CreateServer(request.ToQpServer())
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		AuditRequest(r, models.AuditActionServerCreate, server.Token, "", request, "created awaiting pairing")
+
+		response.Server = models.NewQpServerStatus(server)
+		response.ParseSuccess(fmt.Sprintf("created with success, token: %s, awaiting pairing", server.Token))
+		RespondSuccess(w, response)
+		return
+	}
+
+	response := &models.QpServersResponse{}
+	for _, server := range models.WhatsappService.Servers {
+		if server != nil {
+			response.Servers = append(response.Servers, models.NewQpServerStatus(server))
+		}
+	}
+
+	sort.SliceStable(response.Servers, func(i, j int) bool {
+		return response.Servers[i].Token < response.Servers[j].Token
+	})
+
+	response.Total = len(response.Servers)
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route GET|DELETE "/servers/{token}"
+
+	Requires master key or an api key from an admin user with manage scope
+	GET gets server with live status
+	DELETE logs out, removes the server and its queued deliveries and messages
+
+</summary>
+*/
+func ServerController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpServerResponse{}
+
+	err := EnsureAdministrator(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	server, err := models.GetServerFromToken(GetToken(r))
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		err = models.WhatsappService.Delete(server)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		AuditRequest(r, models.AuditActionServerDelete, server.Token, server.Wid, nil, "server deleted")
+		response.ParseSuccess("server deleted")
+		RespondSuccess(w, response)
+		return
+	}
+
+	response.Server = models.NewQpServerStatus(server)
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/servers/{token}/{action}"
+
+	Requires master key or an api key from an admin user with manage scope
+	Actions: start, stop, restart or logout (unlinks the device, keeping settings for a new pairing)
+
+</summary>
+*/
+func ServerLifecycleController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpServerResponse{}
+
+	err := EnsureAdministrator(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	server, err := models.GetServerFromToken(GetToken(r))
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	action := models.GetRequestParameter(r, "action")
+	switch action {
+	case "start":
+		err = server.Start()
+	case "stop":
+		err = server.Stop("api")
+	case "restart":
+		err = server.Restart()
+	case "logout":
+		err = server.Logout()
+	default:
+		err = fmt.Errorf("invalid action: {%s}, try {start,stop,restart,logout}", action)
+	}
+
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	AuditRequest(r, models.AuditActionServerLifecycle, server.Token, server.Wid, nil, action)
+
+	response.Server = models.NewQpServerStatus(server)
+	response.ParseSuccess(fmt.Sprintf("%s requested with success", action))
+	RespondSuccess(w, response)
+}
+
+//endregion
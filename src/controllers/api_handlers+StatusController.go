@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	metrics "github.com/nocodeleaks/quepasa/metrics"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - STATUS
+
+/*
+<summary>
+
+	Renders route GET|POST "/status"
+
+	GET lists received status (stories) on cache, requires broadcasts handling
+	* attachments are available on GET "/download/{messageid}"
+
+	POST publishes a status to contacts allowed by whatsapp status privacy settings, or only to recipients
+	Body parameters: same as "/send", {"text": "{text}", "url": "{url}", "content": "{base64}", "filename": "{filename}", "recipients": ["{phone or wid}"]}
+	* recipients are not available with whitelist privacy, blacklisted contacts are always excluded
+
+</summary>
+*/
+func StatusController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	server, err := GetServer(r)
+	if err != nil {
+		response := &models.QpResponse{}
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		response := &models.QpStatusResponse{}
+		response.Messages = server.GetStatuses()
+		response.Total = len(response.Messages)
+		RespondSuccess(w, response)
+		return
+	}
+
+	request := &models.QpStatusRequest{}
+	if r.ContentLength > 0 {
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			metrics.MessageSendErrors.Inc()
+			response := &models.QpSendResponse{}
+			response.ParseError(fmt.Errorf("invalid json body: %s", err.Error()))
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	request.ChatId = whatsapp.WhatsappStatusChatId
+	SendAnyContent(w, r, &request.QpSendAnyRequest, server)
+}
+
+/*
+<summary>
+
+	Renders route GET "/status/privacy"
+
+	Gets status audience from whatsapp privacy settings
+	* contacts: all contacts, whitelist: only listed, blacklist: contacts except listed
+
+</summary>
+*/
+func StatusPrivacyController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpStatusResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	privacy, err := server.GetStatusPrivacy()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Privacy = privacy
+	RespondSuccess(w, response)
+}
+
+//endregion
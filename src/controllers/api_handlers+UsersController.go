@@ -0,0 +1,369 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - USERS
+
+// gets user from {username} path parameter, with assigned servers
+func GetUserInfo(r *http.Request) (*models.QpUser, *models.QpUserInfo, error) {
+	username := models.GetRequestParameter(r, "username")
+	if len(username) == 0 {
+		return nil, nil, fmt.Errorf("username missing")
+	}
+
+	db := models.WhatsappService.DB
+	user, err := db.Users.Find(username)
+	if err != nil {
+		return nil, nil, fmt.Errorf("user not found: %s", username)
+	}
+
+	info := user.GetInfo()
+	info.Servers, err = db.Users.GetServers(username)
+	return user, info, err
+}
+
+/*
+<summary>
+
+	Renders route GET|POST "/users"
+
+	Requires master key or an api key from an admin user with manage scope
+	GET lists users, roles and assigned servers
+	POST creates an user
+	Body parameters: {"username": "{username}", "password": "{password}", "role": "admin|operator|readonly"}
+
+</summary>
+*/
+func UsersController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpUsersResponse{}
+
+	err := EnsureAdministrator(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	db := models.WhatsappService.DB
+	if r.Method == http.MethodPost {
+		request := &models.QpUserRequest{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		request.Username = strings.TrimSpace(request.Username)
+		if len(request.Username) == 0 {
+			err = fmt.Errorf("username missing")
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		request.Role, err = models.FormatUserRole(request.Role)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		exists, err := db.Users.Exists(request.Username)
+		if err == nil && exists {
+			err = fmt.Errorf("user already exists: %s", request.Username)
+		}
+
+		if err == nil {
+			_, err = db.Users.Create(request.Username, request.Password)
+		}
+
+		if err == nil {
+			err = db.Users.UpdateRole(request.Username, request.Role)
+		}
+
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		AuditRequest(r, models.AuditActionUserCreate, "", request.Username, nil, "role: "+request.Role)
+		response.ParseSuccess(fmt.Sprintf("created with success, user: %s, role: %s", request.Username, request.Role))
+	}
+
+	users, err := db.Users.FindAll()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	for _, user := range users {
+		info := user.GetInfo()
+		info.Servers, _ = db.Users.GetServers(user.Username)
+		response.Users = append(response.Users, info)
+	}
+
+	response.Total = len(response.Users)
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route GET|PUT "/users/{username}"
+
+	Requires master key or an api key from an admin user with manage scope
+	GET gets user role and assigned servers
+	PUT updates role, api keys scopes are limited by the new role
+	Body parameters: {"role": "admin|operator|readonly"}
+
+</summary>
+*/
+func UserInfoController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpUserResponse{}
+
+	err := EnsureAdministrator(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	user, info, err := GetUserInfo(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		request := &models.QpUserRequest{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		role, err := models.FormatUserRole(request.Role)
+		if err == nil {
+			err = models.WhatsappService.DB.Users.UpdateRole(user.Username, role)
+		}
+
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		info.Role = role
+		AuditRequest(r, models.AuditActionUserUpdate, "", user.Username, nil, "role: "+role)
+		response.ParseSuccess(fmt.Sprintf("updated with success, role: %s", role))
+	}
+
+	response.User = info
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route POST "/users/{username}/servers" and DELETE "/users/{username}/servers/{server}"
+
+	Requires master key or an api key from an admin user with manage scope
+	Assigns an existing server to the user, besides owned ones
+	Body parameters: {"token": "{server token}"}
+
+</summary>
+*/
+func UserServersController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpUserResponse{}
+
+	err := EnsureAdministrator(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	user, info, err := GetUserInfo(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	db := models.WhatsappService.DB
+	if r.Method == http.MethodDelete {
+		token := models.GetRequestParameter(r, "server")
+		affected, err := db.Users.RemoveServer(user.Username, token)
+		if err == nil && affected == 0 {
+			err = fmt.Errorf("server not assigned to user: %s", token)
+		}
+
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		AuditRequest(r, models.AuditActionUserServer, token, user.Username, nil, "unassigned")
+		response.ParseSuccess(fmt.Sprintf("unassigned with success, server: %s", token))
+	} else {
+		request := &models.QpUserServerRequest{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		server, err := models.GetServerFromToken(request.Token)
+		if err == nil {
+			err = db.Users.AddServer(user.Username, server.Token)
+		}
+
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		AuditRequest(r, models.AuditActionUserServer, server.Token, user.Username, nil, "assigned")
+		response.ParseSuccess(fmt.Sprintf("assigned with success, server: %s", server.Token))
+	}
+
+	info.Servers, _ = db.Users.GetServers(user.Username)
+	response.User = info
+	RespondSuccess(w, response)
+}
+
+/*
+<summary>
+
+	Renders route GET|POST "/users/{username}/keys" and DELETE "/users/{username}/keys/{keyid}"
+
+	Requires master key or an api key from an admin user with manage scope
+	GET lists api keys, without secrets
+	POST creates an api key, the plain key is returned only once, use as X-QUEPASA-APIKEY header
+	Body parameters: {"name": "{name}", "scopes": ["send", "receive", "webhooks", "manage"], "server": "{optional token}", "expires": "{optional RFC3339}"}
+
+</summary>
+*/
+func UserKeysController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpApiKeysResponse{}
+
+	err := EnsureAdministrator(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	user, _, err := GetUserInfo(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	db := models.WhatsappService.DB
+	switch r.Method {
+	case http.MethodPost:
+		request := &models.QpApiKeyRequest{}
+		err = decodeJsonBody(r, request)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		key := &models.QpApiKey{
+			Id:        uuid.New().String(),
+			Username:  user.Username,
+			Name:      request.Name,
+			Scopes:    strings.Join(request.Scopes, ","),
+			Server:    request.Server,
+			Expires:   request.Expires,
+			Timestamp: time.Now().UTC(),
+		}
+
+		err = key.FormatScopes(user.GetRole())
+		if err == nil && len(key.Server) > 0 {
+			_, err = models.GetServerFromToken(key.Server)
+		}
+
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Key, err = key.Generate()
+		if err == nil {
+			err = db.ApiKeys.Add(key)
+		}
+
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		AuditRequest(r, models.AuditActionApiKeyCreate, key.Server, key.Id, nil, fmt.Sprintf("user: %s, scopes: %s", user.Username, key.Scopes))
+		response.ParseSuccess(fmt.Sprintf("created with success, id: %s, store the key now, it will not be shown again", key.Id))
+	case http.MethodDelete:
+		id := models.GetRequestParameter(r, "keyid")
+		affected, err := db.ApiKeys.Remove(user.Username, id)
+		if err == nil && affected == 0 {
+			err = fmt.Errorf("api key not found: %s", id)
+		}
+
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		AuditRequest(r, models.AuditActionApiKeyDelete, "", id, nil, "user: "+user.Username)
+		response.ParseSuccess(fmt.Sprintf("deleted with success, id: %s", id))
+	}
+
+	response.Keys, err = db.ApiKeys.FindAll(user.Username)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Total = len(response.Keys)
+	RespondSuccess(w, response)
+}
+
+//endregion
@@ -0,0 +1,325 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	websocket "github.com/gorilla/websocket"
+	metrics "github.com/nocodeleaks/quepasa/metrics"
+	models "github.com/nocodeleaks/quepasa/models"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+//region CONTROLLER - WEBSOCKET
+
+// Maximum command size allowed from peer, send commands may have embed base64 content
+const WebSocketCommandMaxSize = 32 << 20
+
+/*
+<summary>
+
+	Renders route GET "/ws" and "/ws/{token}"
+
+	Pushes messages, receipts and presence updates with the same json of webhooks (QpWebhookPayload)
+	Accepts commands: send (same body of send endpoint), subscribe (filters by chat and type), unsubscribe and ping
+	Commands are answered with {"action", "requestid", "response"}
+	Discarded events due to slow reading are notified as {"action": "lagged", "response": {"dropped": n}}
+
+</summary>
+*/
+func WebSocketController(w http.ResponseWriter, r *http.Request) {
+
+	server, err := GetServer(r)
+	if err != nil {
+		response := &models.QpResponse{}
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// api keys without send scope can only receive events
+	_, senderr := GetServerWithScope(r, models.ApiScopeSend)
+
+	var upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logentry := server.GetLogger()
+		logentry.Errorf("(websocket): upgrade error: %s", err.Error())
+		return
+	}
+
+	client := &WebSocketEventsClient{
+		server:     server,
+		conn:       conn,
+		request:    r,
+		senderr:    senderr,
+		subscriber: models.EventHub.Subscribe(server.Token),
+		replies:    make(chan []byte, 64),
+		done:       make(chan struct{}),
+	}
+
+	go client.writePump()
+	client.readPump()
+}
+
+// Connection of a websocket events client, with its own subscription filters
+type WebSocketEventsClient struct {
+	server  *models.QpWhatsappServer
+	conn    *websocket.Conn
+	request *http.Request
+
+	// not authorized to send messages
+	senderr error
+
+	subscriber *models.QpEventSubscriber
+	replies    chan []byte
+	done       chan struct{}
+
+	filters *models.QpWebhookFilters
+	mutex   sync.RWMutex
+}
+
+// reads commands until the connection is closed
+func (source *WebSocketEventsClient) readPump() {
+	defer func() {
+		models.EventHub.Unsubscribe(source.subscriber)
+		close(source.done)
+		source.conn.Close()
+	}()
+
+	source.conn.SetReadLimit(WebSocketCommandMaxSize)
+	source.conn.SetReadDeadline(time.Now().Add(pongWait))
+	source.conn.SetPongHandler(func(string) error { source.conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
+
+	for {
+		_, data, err := source.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived, websocket.CloseAbnormalClosure) {
+				logentry := source.server.GetLogger()
+				logentry.Warnf("(websocket): unexpected close: %s", err.Error())
+			}
+			return
+		}
+
+		command := &models.QpWebSocketCommand{}
+		err = json.Unmarshal(data, command)
+		if err != nil {
+			response := &models.QpResponse{}
+			response.ParseError(fmt.Errorf("invalid json command: %s", err.Error()))
+			source.Reply(command, response)
+			continue
+		}
+
+		source.Execute(command, data)
+	}
+}
+
+func (source *WebSocketEventsClient) Execute(command *models.QpWebSocketCommand, data []byte) {
+	response := &models.QpResponse{}
+
+	switch command.GetAction() {
+	case models.WebSocketActionSend:
+		// may download content, should not block reading
+		go source.Send(command, data)
+		return
+	case models.WebSocketActionSubscribe:
+		err := command.Filters.Validate()
+		if err != nil {
+			response.ParseError(err)
+			break
+		}
+
+		source.mutex.Lock()
+		source.filters = command.Filters
+		source.mutex.Unlock()
+
+		response.ParseSuccess("subscribed")
+	case models.WebSocketActionUnsubscribe:
+		source.mutex.Lock()
+		source.filters = nil
+		source.mutex.Unlock()
+
+		response.ParseSuccess("unsubscribed")
+	case models.WebSocketActionPing:
+		response.ParseSuccess("pong")
+	default:
+		response.ParseError(fmt.Errorf("invalid action: {%s}, try {send,subscribe,unsubscribe,ping}", command.Action))
+	}
+
+	source.Reply(command, response)
+}
+
+// sends a message through the same flow of send endpoint
+func (source *WebSocketEventsClient) Send(command *models.QpWebSocketCommand, data []byte) {
+	response := &models.QpSendResponse{}
+	if source.senderr != nil {
+		response.ParseError(source.senderr)
+		source.Reply(command, response)
+		return
+	}
+
+	r := source.NewCommandRequest(data)
+	request := &command.QpSendAnyRequest
+	err := request.EnsureValidChatId(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		source.Reply(command, response)
+		return
+	}
+
+	writer := &BufferedResponseWriter{}
+	SendAnyContent(writer, r, request, source.server)
+	source.ReplyRaw(command, writer.Body.Bytes())
+}
+
+// Builds a fresh request for each command, with the command as body.
+// Query and headers of the handshake (idempotency key, sendat, inreply, etc) do not apply to commands,
+// only credentials are kept, so audit records the same actor
+func (source *WebSocketEventsClient) NewCommandRequest(data []byte) *http.Request {
+	r, _ := http.NewRequestWithContext(source.request.Context(), http.MethodPost, source.request.URL.Path, bytes.NewReader(data))
+	r.Header.Set("Content-Type", "application/json")
+
+	if apikey := GetApiKey(source.request); len(apikey) > 0 {
+		r.Header.Set("X-QUEPASA-APIKEY", apikey)
+	}
+
+	if masterkey := GetMasterKey(source.request); len(masterkey) > 0 {
+		r.Header.Set("X-QUEPASA-MASTERKEY", masterkey)
+	}
+
+	r.Header.Set("X-QUEPASA-TOKEN", source.server.Token)
+	return r
+}
+
+func (source *WebSocketEventsClient) Reply(command *models.QpWebSocketCommand, response interface{}) {
+	content, err := json.Marshal(response)
+	if err != nil {
+		logentry := source.server.GetLogger()
+		logentry.Errorf("(websocket): error on marshal response: %s", err.Error())
+		return
+	}
+
+	source.ReplyRaw(command, content)
+}
+
+func (source *WebSocketEventsClient) ReplyRaw(command *models.QpWebSocketCommand, content []byte) {
+	reply := &models.QpWebSocketResponse{
+		Action:    command.GetAction(),
+		RequestId: command.RequestId,
+		Response:  bytes.TrimSpace(content),
+	}
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		logentry := source.server.GetLogger()
+		logentry.Errorf("(websocket): error on marshal reply: %s", err.Error())
+		return
+	}
+
+	select {
+	case source.replies <- data:
+	case <-source.done:
+	}
+}
+
+// indicates that a message should be pushed, based on connection filters
+func (source *WebSocketEventsClient) Match(message *whatsapp.WhatsappMessage) bool {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	return source.filters.Match(message)
+}
+
+// writes events and replies until the connection is closed
+func (source *WebSocketEventsClient) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		source.conn.Close()
+	}()
+
+	for {
+		var data []byte
+
+		select {
+		case <-source.done:
+			return
+		case data = <-source.replies:
+		case event := <-source.subscriber.Events:
+
+			// status and state events are not webhook payloads
+			message, ok := event.Data.(*whatsapp.WhatsappMessage)
+			if !ok || !source.Match(message) {
+				continue
+			}
+
+			payload := &models.QpWebhookPayload{WhatsappMessage: message}
+			content, err := json.Marshal(payload)
+			if err != nil {
+				logentry := source.server.GetLogger()
+				logentry.Errorf("(websocket): error on marshal payload: %s", err.Error())
+				continue
+			}
+			data = content
+		case <-source.subscriber.Lagged:
+			lagged := source.subscriber.TakeLagged()
+			if lagged == nil {
+				continue
+			}
+
+			response, _ := json.Marshal(lagged.Data)
+			content, err := json.Marshal(&models.QpWebSocketResponse{Action: lagged.Type, Response: response})
+			if err != nil {
+				continue
+			}
+			data = content
+		case <-ticker.C:
+			source.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := source.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+			continue
+		}
+
+		source.conn.SetWriteDeadline(time.Now().Add(writeWait))
+		if err := source.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			return
+		}
+	}
+}
+
+// Response writer that keeps the content on memory, used to reuse http flows
+type BufferedResponseWriter struct {
+	header http.Header
+	Code   int
+	Body   bytes.Buffer
+}
+
+func (source *BufferedResponseWriter) Header() http.Header {
+	if source.header == nil {
+		source.header = http.Header{}
+	}
+	return source.header
+}
+
+func (source *BufferedResponseWriter) WriteHeader(code int) {
+	source.Code = code
+}
+
+func (source *BufferedResponseWriter) Write(content []byte) (int, error) {
+	if source.Code == 0 {
+		source.Code = http.StatusOK
+	}
+	return source.Body.Write(content)
+}
+
+//endregion
@@ -71,6 +71,7 @@ func WebhookController(w http.ResponseWriter, r *http.Request) {
 			RespondSuccess(w, response)
 			if affected > 0 {
 				logger.Infof("updating webhook url=%s, items affected: %v", webhook.Url, affected)
+				AuditRequest(r, models.AuditActionWebhookUpdate, server.Token, webhook.Url, body, fmt.Sprintf("items affected: %v", affected))
 			}
 		}
 		return
@@ -85,6 +86,7 @@ func WebhookController(w http.ResponseWriter, r *http.Request) {
 			RespondSuccess(w, response)
 			if affected > 0 {
 				logger.Infof("removing webhook url=%s, items affected: %v", webhook.Url, affected)
+				AuditRequest(r, models.AuditActionWebhookDelete, server.Token, webhook.Url, body, fmt.Sprintf("items affected: %v", affected))
 			}
 		}
 		return
@@ -102,4 +104,80 @@ func WebhookController(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+/*
+<summary>
+
+	Renders route POST "/webhook/secret"
+	Rotates the secret used to sign payloads of an existing webhook
+
+	Any of then, at this order of priority
+	Body parameters: {"url": "{url}"}
+	Url parameters: ?url={url}
+	Header parameters: X-QUEPASA-URL = {url}
+
+</summary>
+*/
+func WebhookSecretController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpWebhookResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// reading body to avoid converting to json if empty
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpWebhook{}
+	if len(body) > 0 {
+		err = json.Unmarshal(body, request)
+		if err != nil {
+			jsonError := fmt.Errorf("error converting body to json: %v", err.Error())
+			response.ParseError(jsonError)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	url := request.Url
+	if len(url) == 0 {
+		url = models.GetRequestParameter(r, "url")
+	}
+
+	webhook := server.GetWebHook(url)
+	if webhook == nil {
+		err = fmt.Errorf("webhook not found for url: %s", url)
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	_, err = webhook.RotateSecret()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	logentry := server.GetLogger()
+	logentry.Infof("webhook secret rotated, url=%s", url)
+	AuditRequest(r, models.AuditActionWebhookSecret, server.Token, url, nil, "secret rotated")
+
+	response.Affected = 1
+	response.Webhooks = []*models.QpWebhook{webhook.QpWebhook}
+	response.ParseSuccess("secret rotated with success")
+	RespondSuccess(w, response)
+}
+
 //endregion
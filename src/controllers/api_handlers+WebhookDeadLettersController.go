@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	models "github.com/nocodeleaks/quepasa/models"
+)
+
+//region CONTROLLER - WEBHOOK DEAD LETTERS
+
+/*
+<summary>
+
+	Renders route GET|DELETE "/webhook/deadletters" and POST "/webhook/deadletters/replay"
+
+	Optional filters, at this order of priority
+	Url parameters: ?url={url}&id={id}
+	Header parameters: X-QUEPASA-URL = {url}, X-QUEPASA-ID = {id}
+
+</summary>
+*/
+func WebhookDeadLettersController(w http.ResponseWriter, r *http.Request) {
+
+	// setting default response type as json
+	w.Header().Set("Content-Type", "application/json")
+
+	response := &models.QpWebhookDeadLettersResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if server.WebhookQueue == nil {
+		err = fmt.Errorf("webhook queue not attached")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	logentry := server.GetLogger()
+
+	url := models.GetRequestParameter(r, "url")
+	id := models.GetRequestParameter(r, "id")
+
+	switch r.Method {
+	case http.MethodPost:
+		affected, err := server.WebhookQueue.Replay(url, id)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Affected = affected
+		response.ParseSuccess("replayed with success")
+		RespondSuccess(w, response)
+		if affected > 0 {
+			logentry.Infof("replaying webhook dead letters url=%s, items affected: %v", url, affected)
+		}
+		return
+	case http.MethodDelete:
+		affected, err := server.WebhookQueue.Purge(url, id)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Affected = affected
+		response.ParseSuccess("purged with success")
+		RespondSuccess(w, response)
+		if affected > 0 {
+			logentry.Infof("purging webhook dead letters url=%s, items affected: %v", url, affected)
+		}
+		return
+	default:
+		deadletters, err := server.WebhookQueue.DeadLetters(url)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Total = len(deadletters)
+		response.DeadLetters = deadletters
+		if len(url) > 0 {
+			response.ParseSuccess(fmt.Sprintf("getting with filter, url=%s", url))
+		} else {
+			response.ParseSuccess("getting without filter")
+		}
+
+		RespondSuccess(w, response)
+		return
+	}
+}
+
+//endregion
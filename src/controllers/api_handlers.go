@@ -12,20 +12,25 @@ import (
 const CurrentAPIVersion string = "v4"
 
 func RegisterAPIControllers(r chi.Router) {
+	// api key scope required by each route
+	receive := r.With(MiddlewareForApiScope(models.ApiScopeReceive))
+	send := r.With(MiddlewareForApiScope(models.ApiScopeSend))
+	webhooks := r.With(MiddlewareForApiScope(models.ApiScopeWebhooks))
+	manage := r.With(MiddlewareForApiScope(models.ApiScopeManage))
 
 	aliases := []string{"/current", "", "/" + CurrentAPIVersion}
 	for _, endpoint := range aliases {
 
 		// CONTROL METHODS ************************
 		// ----------------------------------------
-		r.Get(endpoint+"/info", InformationController)
-		r.Patch(endpoint+"/info", InformationController)
-		r.Delete(endpoint+"/info", InformationController)
+		receive.Get(endpoint+"/info", InformationController)
+		manage.Patch(endpoint+"/info", InformationController)
+		manage.Delete(endpoint+"/info", InformationController)
 
-		r.Get(endpoint+"/scan", ScannerController)
-		r.Get(endpoint+"/paircode", PairCodeController)
+		manage.Get(endpoint+"/scan", ScannerController)
+		manage.Get(endpoint+"/paircode", PairCodeController)
 
-		r.Get(endpoint+"/command", CommandController)
+		manage.Get(endpoint+"/command", CommandController)
 
 		// ----------------------------------------
 		// CONTROL METHODS ************************
@@ -33,79 +38,196 @@ func RegisterAPIControllers(r chi.Router) {
 		// SENDING MSG ----------------------------
 		// ----------------------------------------
 
-		r.Get(endpoint+"/message/{messageid}", GetMessageController)
-		r.Get(endpoint+"/message", GetMessageController)
+		receive.Get(endpoint+"/message/{messageid}", GetMessageController)
+		receive.Get(endpoint+"/message", GetMessageController)
 
-		r.Delete(endpoint+"/message/{messageid}", RevokeController)
-		r.Delete(endpoint+"/message", RevokeController)
+		send.Put(endpoint+"/message/{messageid}", EditController)
+		send.Put(endpoint+"/message", EditController)
+
+		send.Delete(endpoint+"/message/{messageid}", RevokeController)
+		send.Delete(endpoint+"/message", RevokeController)
+
+		send.Post(endpoint+"/react", ReactController)
+		send.Post(endpoint+"/read", ReadController)
 
 		// used to send alert msgs via url, triggers on monitor systems like zabbix
-		r.Get(endpoint+"/send", SendAny)
+		send.Get(endpoint+"/send", SendAny)
 
-		r.Post(endpoint+"/send", SendAny)
-		r.Post(endpoint+"/send/{chatid}", SendAny)
+		send.Post(endpoint+"/send", SendAny)
+		send.Post(endpoint+"/send/{chatid}", SendAny)
 
 		// obsolete, marked for remove (2024/10/22)
-		r.Post(endpoint+"/sendtext", SendAny)
-		r.Post(endpoint+"/sendtext/{chatid}", SendAny)
+		send.Post(endpoint+"/sendtext", SendAny)
+		send.Post(endpoint+"/sendtext/{chatid}", SendAny)
 
 		// SENDING MSG ATTACH ---------------------
 
 		// deprecated, discard/remove on next version
-		r.Post(endpoint+"/senddocument", SendDocumentAPIHandlerV2)
+		send.Post(endpoint+"/senddocument", SendDocumentAPIHandlerV2)
+
+		send.Post(endpoint+"/sendurl", SendAny)
+		send.Post(endpoint+"/sendbinary/{chatid}/{filename}/{text}", SendDocumentFromBinary)
+		send.Post(endpoint+"/sendbinary/{chatid}/{filename}", SendDocumentFromBinary)
+		send.Post(endpoint+"/sendbinary/{chatid}", SendDocumentFromBinary)
+		send.Post(endpoint+"/sendbinary", SendDocumentFromBinary)
+		send.Post(endpoint+"/sendencoded", SendAny)
+
+		// status (stories)
+		receive.Get(endpoint+"/status", StatusController)
+		send.Post(endpoint+"/status", StatusController)
+		receive.Get(endpoint+"/status/privacy", StatusPrivacyController)
 
-		r.Post(endpoint+"/sendurl", SendAny)
-		r.Post(endpoint+"/sendbinary/{chatid}/{filename}/{text}", SendDocumentFromBinary)
-		r.Post(endpoint+"/sendbinary/{chatid}/{filename}", SendDocumentFromBinary)
-		r.Post(endpoint+"/sendbinary/{chatid}", SendDocumentFromBinary)
-		r.Post(endpoint+"/sendbinary", SendDocumentFromBinary)
-		r.Post(endpoint+"/sendencoded", SendAny)
+		// queued and scheduled messages
+		receive.Get(endpoint+"/sendqueue", SendQueueController)
+		send.Delete(endpoint+"/sendqueue", SendQueueController)
 
 		// ----------------------------------------
 		// SENDING MSG ----------------------------
 
-		r.Get(endpoint+"/receive", ReceiveAPIHandler)
-		r.Post(endpoint+"/attachment", AttachmentAPIHandlerV2)
+		receive.Get(endpoint+"/receive", ReceiveAPIHandler)
+		receive.Get(endpoint+"/search", SearchController)
+		receive.Post(endpoint+"/attachment", AttachmentAPIHandlerV2)
 
-		r.Get(endpoint+"/download/{messageid}", DownloadController)
-		r.Get(endpoint+"/download", DownloadController)
+		receive.Get(endpoint+"/download/{messageid}", DownloadController)
+		receive.Get(endpoint+"/download", DownloadController)
 
 		// PICTURE INFO | DATA --------------------
 		// ----------------------------------------
 
-		r.Post(endpoint+"/picinfo", PictureController)
-		r.Get(endpoint+"/picinfo/{chatid}/{pictureid}", PictureController)
-		r.Get(endpoint+"/picinfo/{chatid}", PictureController)
-		r.Get(endpoint+"/picinfo", PictureController)
+		receive.Post(endpoint+"/picinfo", PictureController)
+		receive.Get(endpoint+"/picinfo/{chatid}/{pictureid}", PictureController)
+		receive.Get(endpoint+"/picinfo/{chatid}", PictureController)
+		receive.Get(endpoint+"/picinfo", PictureController)
 
-		r.Post(endpoint+"/picdata", PictureController)
-		r.Get(endpoint+"/picdata/{chatid}/{pictureid}", PictureController)
-		r.Get(endpoint+"/picdata/{chatid}", PictureController)
-		r.Get(endpoint+"/picdata", PictureController)
+		receive.Post(endpoint+"/picdata", PictureController)
+		receive.Get(endpoint+"/picdata/{chatid}/{pictureid}", PictureController)
+		receive.Get(endpoint+"/picdata/{chatid}", PictureController)
+		receive.Get(endpoint+"/picdata", PictureController)
 
 		// ----------------------------------------
 		// PICTURE INFO | DATA --------------------
 
-		r.Post(endpoint+"/webhook", WebhookController)
-		r.Get(endpoint+"/webhook", WebhookController)
-		r.Delete(endpoint+"/webhook", WebhookController)
+		webhooks.Post(endpoint+"/webhook", WebhookController)
+		webhooks.Get(endpoint+"/webhook", WebhookController)
+		webhooks.Delete(endpoint+"/webhook", WebhookController)
+		webhooks.Post(endpoint+"/webhook/secret", WebhookSecretController)
+
+		webhooks.Get(endpoint+"/webhook/deadletters", WebhookDeadLettersController)
+		webhooks.Post(endpoint+"/webhook/deadletters/replay", WebhookDeadLettersController)
+		webhooks.Delete(endpoint+"/webhook/deadletters", WebhookDeadLettersController)
 
 		// INVITE METHODS ************************
 		// ----------------------------------------
 
-		r.Get(endpoint+"/invite", InviteController)
-		r.Get(endpoint+"/invite/{chatid}", InviteController)
+		receive.Get(endpoint+"/invite", InviteController)
+		receive.Get(endpoint+"/invite/{chatid}", InviteController)
 
 		// ----------------------------------------
 		// INVITE METHODS ************************
 
-		r.Get(endpoint+"/contacts", ContactsController)
-		r.Post(endpoint+"/isonwhatsapp", IsOnWhatsappController)
+		// PRESENCE METHODS **********************
+		// ----------------------------------------
+
+		send.Post(endpoint+"/presence", PresenceController)
+		receive.Post(endpoint+"/presence/subscribe", PresenceSubscribeController)
+		send.Post(endpoint+"/chatpresence", ChatPresenceController)
+
+		// ----------------------------------------
+		// PRESENCE METHODS **********************
+
+		// GROUPS METHODS ************************
+		// ----------------------------------------
+
+		receive.Get(endpoint+"/groups", GroupsController)
+		send.Post(endpoint+"/groups", GroupsController)
+		send.Post(endpoint+"/groups/join", GroupJoinController)
+		receive.Get(endpoint+"/groups/{chatid}", GroupController)
+		send.Put(endpoint+"/groups/{chatid}", GroupController)
+		send.Post(endpoint+"/groups/{chatid}/participants", GroupParticipantsController)
+		send.Put(endpoint+"/groups/{chatid}/picture", GroupPictureController)
+		send.Post(endpoint+"/groups/{chatid}/invite/revoke", GroupInviteRevokeController)
+
+		// ----------------------------------------
+		// GROUPS METHODS ************************
+
+		// NEWSLETTERS METHODS *******************
+		// ----------------------------------------
+
+		receive.Get(endpoint+"/newsletters", NewslettersController)
+		send.Post(endpoint+"/newsletters/follow", NewsletterFollowController)
+		receive.Get(endpoint+"/newsletters/{chatid}", NewsletterController)
+		send.Delete(endpoint+"/newsletters/{chatid}", NewsletterController)
+		receive.Get(endpoint+"/newsletters/{chatid}/messages", NewsletterMessagesController)
+		send.Post(endpoint+"/newsletters/{chatid}/messages", NewsletterMessagesController)
+
+		// ----------------------------------------
+		// NEWSLETTERS METHODS *******************
+
+		// incoming call rules
+		manage.Get(endpoint+"/calls/rules", CallRulesController)
+		manage.Put(endpoint+"/calls/rules", CallRulesController)
+		manage.Delete(endpoint+"/calls/rules", CallRulesController)
+
+		// auto reply and keyword rules
+		manage.Get(endpoint+"/rules", AutoRulesController)
+		manage.Post(endpoint+"/rules", AutoRulesController)
+		manage.Get(endpoint+"/rules/{ruleid}", AutoRuleController)
+		manage.Put(endpoint+"/rules/{ruleid}", AutoRuleController)
+		manage.Delete(endpoint+"/rules/{ruleid}", AutoRuleController)
+
+		// chatwoot channel
+		manage.Get(endpoint+"/chatwoot", ChatwootController)
+		manage.Put(endpoint+"/chatwoot", ChatwootController)
+		manage.Delete(endpoint+"/chatwoot", ChatwootController)
+
+		// authenticated by the generated secret only, the url is visible to chatwoot admins
+		r.Post(endpoint+"/chatwoot/webhook/{secret}", ChatwootWebhookController)
+
+		// users, roles and api keys, requires master key or admin api key
+		manage.Get(endpoint+"/users", UsersController)
+		manage.Post(endpoint+"/users", UsersController)
+		manage.Get(endpoint+"/users/{username}", UserInfoController)
+		manage.Put(endpoint+"/users/{username}", UserInfoController)
+		manage.Post(endpoint+"/users/{username}/servers", UserServersController)
+		manage.Delete(endpoint+"/users/{username}/servers/{server}", UserServersController)
+		manage.Get(endpoint+"/users/{username}/keys", UserKeysController)
+		manage.Post(endpoint+"/users/{username}/keys", UserKeysController)
+		manage.Delete(endpoint+"/users/{username}/keys/{keyid}", UserKeysController)
+
+		// administrative and send actions, requires master key or admin api key
+		manage.Get(endpoint+"/audit", AuditController)
+
+		// servers lifecycle, requires master key or admin api key
+		manage.Get(endpoint+"/servers", ServersController)
+		manage.Post(endpoint+"/servers", ServersController)
+		manage.Get(endpoint+"/servers/{token}", ServerController)
+		manage.Delete(endpoint+"/servers/{token}", ServerController)
+		manage.Post(endpoint+"/servers/{token}/{action}", ServerLifecycleController)
+
+		receive.Get(endpoint+"/contacts", ContactsController)
+		receive.Post(endpoint+"/isonwhatsapp", IsOnWhatsappController)
 
 		// IF YOU LOVE YOUR FREEDOM, DO NOT USE THAT
 		// IT WAS DEVELOPED IN A MOMENT OF WEAKNESS
 		// DONT BE THAT GUY !
-		r.Post(endpoint+"/spam", Spam)
+		send.Post(endpoint+"/spam", Spam)
+	}
+}
+
+// long running streams, registered outside the api request timeout
+func RegisterAPIStreamControllers(r chi.Router) {
+	receive := r.With(MiddlewareForApiScope(models.ApiScopeReceive))
+
+	aliases := []string{"/current", "", "/" + CurrentAPIVersion}
+	for _, endpoint := range aliases {
+
+		// server sent events
+		receive.Get(endpoint+"/events", EventsController)
+		receive.Get(endpoint+"/events/{token}", EventsController)
+
+		// websocket events and send commands
+		receive.Get(endpoint+"/ws", WebSocketController)
+		receive.Get(endpoint+"/ws/{token}", WebSocketController)
 	}
 }
 
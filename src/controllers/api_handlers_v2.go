@@ -19,18 +19,22 @@ const APIVersion2 string = "v2"
 var ControllerPrefixV2 string = fmt.Sprintf("/%s/bot/{token}", APIVersion2)
 
 func RegisterAPIV2Controllers(r chi.Router) {
+	// api key scope required by each route
+	receive := r.With(MiddlewareForApiScope(models.ApiScopeReceive))
+	send := r.With(MiddlewareForApiScope(models.ApiScopeSend))
+	webhooks := r.With(MiddlewareForApiScope(models.ApiScopeWebhooks))
 
-	r.Get(ControllerPrefixV2, InformationHandlerV2)
-	r.Post(ControllerPrefixV2+"/send", SendAPIHandlerV2)
-	r.Post(ControllerPrefixV2+"/sendtext", SendAPIHandlerV2)
-	r.Get(ControllerPrefixV2+"/receive", ReceiveAPIHandlerV2)
+	receive.Get(ControllerPrefixV2, InformationHandlerV2)
+	send.Post(ControllerPrefixV2+"/send", SendAPIHandlerV2)
+	send.Post(ControllerPrefixV2+"/sendtext", SendAPIHandlerV2)
+	receive.Get(ControllerPrefixV2+"/receive", ReceiveAPIHandlerV2)
 
 	// external for now
-	r.Post(ControllerPrefixV2+"/senddocument", SendDocumentAPIHandlerV2)
-	r.Post(ControllerPrefixV2+"/attachment", AttachmentAPIHandlerV2)
-	r.Post(ControllerPrefixV2+"/webhook", WebHookAPIHandlerV2)
-	r.Get(ControllerPrefixV2+"/webhook", WebHookAPIHandlerV2)
-	r.Delete(ControllerPrefixV2+"/webhook", WebHookAPIHandlerV2)
+	send.Post(ControllerPrefixV2+"/senddocument", SendDocumentAPIHandlerV2)
+	receive.Post(ControllerPrefixV2+"/attachment", AttachmentAPIHandlerV2)
+	webhooks.Post(ControllerPrefixV2+"/webhook", WebHookAPIHandlerV2)
+	webhooks.Get(ControllerPrefixV2+"/webhook", WebHookAPIHandlerV2)
+	webhooks.Delete(ControllerPrefixV2+"/webhook", WebHookAPIHandlerV2)
 }
 
 // InformationController renders route GET "/{version}/bot/{token}"
@@ -98,14 +102,17 @@ func SendAPIHandlerV2(w http.ResponseWriter, r *http.Request) {
 		waMsg.Chat.Title = server.GetChatTitle(waMsg.Chat.Id)
 	}
 
-	sendResponse, err := server.SendMessage(waMsg)
+	sendResponse, queued, err := server.SendMessageOrEnqueue(waMsg, nil)
 	if err != nil {
 		metrics.MessageSendErrors.Inc()
+		AuditSendFailed(r, server, waMsg, request, err)
 		response.ParseError(err)
 		RespondInterface(w, response)
 		return
 	}
 
+	AuditSend(r, server, waMsg, sendResponse.GetId(), queued, request)
+
 	response.Chat.ID = waMsg.Chat.Id
 	response.Chat.UserName = waMsg.Chat.Id
 	response.Chat.Title = waMsg.Chat.Title
@@ -120,6 +127,13 @@ func SendAPIHandlerV2(w http.ResponseWriter, r *http.Request) {
 		MessageId: sendResponse.GetId(),
 	}
 
+	if queued {
+		response.Queued = true
+		response.ParseSuccess("queued with success")
+		RespondSuccess(w, response)
+		return
+	}
+
 	metrics.MessagesSent.Inc()
 	RespondSuccess(w, response)
 }
@@ -217,13 +231,16 @@ func SendDocumentAPIHandlerV2(w http.ResponseWriter, r *http.Request) {
 	waMsg.Attachment = atts.Attach
 	waMsg.Type = whatsapp.GetMessageType(atts.Attach)
 
-	sendResponse, err := server.SendMessage(waMsg)
+	sendResponse, queued, err := server.SendMessageOrEnqueue(waMsg, nil)
 	if err != nil {
 		metrics.MessageSendErrors.Inc()
+		AuditSendFailed(r, server, waMsg, requestV2, err)
 		RespondServerError(server, w, err)
 		return
 	}
 
+	AuditSend(r, server, waMsg, sendResponse.GetId(), queued, requestV2)
+
 	response := &models.QpSendResponseV2{}
 	response.Chat.ID = waMsg.Chat.Id
 	response.Chat.UserName = waMsg.Chat.Id
@@ -239,6 +256,13 @@ func SendDocumentAPIHandlerV2(w http.ResponseWriter, r *http.Request) {
 		MessageId: sendResponse.GetId(),
 	}
 
+	if queued {
+		response.Queued = true
+		response.ParseSuccess("queued with success")
+		RespondSuccess(w, response)
+		return
+	}
+
 	metrics.MessagesSent.Inc()
 	RespondSuccess(w, response)
 }
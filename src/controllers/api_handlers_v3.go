@@ -14,67 +14,71 @@ const APIVersion3 string = "v3"
 var ControllerPrefixV3 string = fmt.Sprintf("/%s/bot/{token}", APIVersion3)
 
 func RegisterAPIV3Controllers(r chi.Router) {
+	// api key scope required by each route
+	receive := r.With(MiddlewareForApiScope(models.ApiScopeReceive))
+	send := r.With(MiddlewareForApiScope(models.ApiScopeSend))
+	webhooks := r.With(MiddlewareForApiScope(models.ApiScopeWebhooks))
 
-	r.Get(ControllerPrefixV3, InformationControllerV3)
+	receive.Get(ControllerPrefixV3, InformationControllerV3)
 
 	// SENDING MSG ----------------------------
 	// ----------------------------------------
 
 	// used to send alert msgs via url, triggers on monitor systems like zabbix
-	r.Get(ControllerPrefixV3+"/send", SendAny)
+	send.Get(ControllerPrefixV3+"/send", SendAny)
 
-	r.Post(ControllerPrefixV3+"/send", SendAny)
-	r.Post(ControllerPrefixV3+"/send/{chatid}", SendAny)
+	send.Post(ControllerPrefixV3+"/send", SendAny)
+	send.Post(ControllerPrefixV3+"/send/{chatid}", SendAny)
 
 	// obsolete, marked for remove (2024/10/22)
-	r.Post(ControllerPrefixV3+"/sendtext", SendAny)
-	r.Post(ControllerPrefixV3+"/sendtext/{chatid}", SendAny)
+	send.Post(ControllerPrefixV3+"/sendtext", SendAny)
+	send.Post(ControllerPrefixV3+"/sendtext/{chatid}", SendAny)
 
 	// SENDING MSG ATTACH ---------------------
 
 	// deprecated, discard/remove on next version
-	r.Post(ControllerPrefixV3+"/senddocument", SendDocumentAPIHandlerV2)
+	send.Post(ControllerPrefixV3+"/senddocument", SendDocumentAPIHandlerV2)
 
-	r.Post(ControllerPrefixV3+"/sendurl", SendAny)
-	r.Post(ControllerPrefixV3+"/sendbinary/{chatid}/{filename}/{text}", SendDocumentFromBinary)
-	r.Post(ControllerPrefixV3+"/sendbinary/{chatid}/{filename}", SendDocumentFromBinary)
-	r.Post(ControllerPrefixV3+"/sendbinary/{chatid}", SendDocumentFromBinary)
-	r.Post(ControllerPrefixV3+"/sendbinary", SendDocumentFromBinary)
-	r.Post(ControllerPrefixV3+"/sendencoded", SendAny)
+	send.Post(ControllerPrefixV3+"/sendurl", SendAny)
+	send.Post(ControllerPrefixV3+"/sendbinary/{chatid}/{filename}/{text}", SendDocumentFromBinary)
+	send.Post(ControllerPrefixV3+"/sendbinary/{chatid}/{filename}", SendDocumentFromBinary)
+	send.Post(ControllerPrefixV3+"/sendbinary/{chatid}", SendDocumentFromBinary)
+	send.Post(ControllerPrefixV3+"/sendbinary", SendDocumentFromBinary)
+	send.Post(ControllerPrefixV3+"/sendencoded", SendAny)
 
 	// ----------------------------------------
 	// SENDING MSG ----------------------------
 
-	r.Get(ControllerPrefixV3+"/receive", ReceiveAPIHandler)
-	r.Post(ControllerPrefixV3+"/attachment", AttachmentAPIHandlerV2)
+	receive.Get(ControllerPrefixV3+"/receive", ReceiveAPIHandler)
+	receive.Post(ControllerPrefixV3+"/attachment", AttachmentAPIHandlerV2)
 
-	r.Get(ControllerPrefixV3+"/download/{messageid}", DownloadController)
-	r.Get(ControllerPrefixV3+"/download", DownloadController)
+	receive.Get(ControllerPrefixV3+"/download/{messageid}", DownloadController)
+	receive.Get(ControllerPrefixV3+"/download", DownloadController)
 
 	// PICTURE INFO | DATA --------------------
 	// ----------------------------------------
 
-	r.Post(ControllerPrefixV3+"/picinfo", PictureController)
-	r.Get(ControllerPrefixV3+"/picinfo/{chatid}/{pictureid}", PictureController)
-	r.Get(ControllerPrefixV3+"/picinfo/{chatid}", PictureController)
-	r.Get(ControllerPrefixV3+"/picinfo", PictureController)
+	receive.Post(ControllerPrefixV3+"/picinfo", PictureController)
+	receive.Get(ControllerPrefixV3+"/picinfo/{chatid}/{pictureid}", PictureController)
+	receive.Get(ControllerPrefixV3+"/picinfo/{chatid}", PictureController)
+	receive.Get(ControllerPrefixV3+"/picinfo", PictureController)
 
-	r.Post(ControllerPrefixV3+"/picdata", PictureController)
-	r.Get(ControllerPrefixV3+"/picdata/{chatid}/{pictureid}", PictureController)
-	r.Get(ControllerPrefixV3+"/picdata/{chatid}", PictureController)
-	r.Get(ControllerPrefixV3+"/picdata", PictureController)
+	receive.Post(ControllerPrefixV3+"/picdata", PictureController)
+	receive.Get(ControllerPrefixV3+"/picdata/{chatid}/{pictureid}", PictureController)
+	receive.Get(ControllerPrefixV3+"/picdata/{chatid}", PictureController)
+	receive.Get(ControllerPrefixV3+"/picdata", PictureController)
 
 	// ----------------------------------------
 	// PICTURE INFO | DATA --------------------
 
-	r.Post(ControllerPrefixV3+"/webhook", WebhookController)
-	r.Get(ControllerPrefixV3+"/webhook", WebhookController)
-	r.Delete(ControllerPrefixV3+"/webhook", WebhookController)
+	webhooks.Post(ControllerPrefixV3+"/webhook", WebhookController)
+	webhooks.Get(ControllerPrefixV3+"/webhook", WebhookController)
+	webhooks.Delete(ControllerPrefixV3+"/webhook", WebhookController)
 
 	// INVITE METHODS ************************
 	// ----------------------------------------
 
-	r.Get(ControllerPrefixV3+"/invite/{chatid}", InviteController)
+	receive.Get(ControllerPrefixV3+"/invite/{chatid}", InviteController)
 
 	// ----------------------------------------
 	// INVITE METHODS ************************
@@ -97,7 +101,7 @@ func InformationControllerV3(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response.ParseSuccess(server)
-	RespondSuccess(w, response)
+	RespondInformation(w, r, response)
 }
 
 //endregion
@@ -13,18 +13,39 @@ import (
 <summary>
 
 	Find a whatsapp server by token passed on Url Path parameters
+	With an user api key, checks the scope required by the request and the servers allowed for the user
 
 </summary>
 */
 func GetServer(r *http.Request) (server *models.QpWhatsappServer, err error) {
+	return GetServerWithScope(r, GetRequiredScope(r))
+}
+
+// Find a whatsapp server by token, with an user api key, checks an specific scope
+func GetServerWithScope(r *http.Request, scope string) (server *models.QpWhatsappServer, err error) {
 	token := GetToken(r)
+
+	apikey := GetApiKey(r)
+	if len(apikey) > 0 {
+		authorization, err := models.GetApiAuthorization(apikey)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(scope) == 0 {
+			return nil, errors.New("api keys are not allowed on this route")
+		}
+
+		return authorization.GetServer(token, scope)
+	}
+
 	return models.GetServerFromToken(token)
 }
 
 // <summary>Find a whatsapp server by token passed on Url Path parameters</summary>
 func GetServerRespondOnError(w http.ResponseWriter, r *http.Request) (server *models.QpWhatsappServer, err error) {
 	token := GetToken(r)
-	server, err = models.GetServerFromToken(token)
+	server, err = GetServer(r)
 	if err != nil {
 		RespondNoContentV2(w, fmt.Errorf("token '%s' not found", token))
 	}
@@ -32,15 +53,87 @@ func GetServerRespondOnError(w http.ResponseWriter, r *http.Request) (server *mo
 }
 
 func GetServerFromMaster(r *http.Request) (server *models.QpWhatsappServer, err error) {
+	err = EnsureAdministrator(r)
+	if err != nil {
+		return
+	}
+
+	return models.GetServerFirstAvailable()
+}
+
+// Checks for master key, or an api key from an admin user with manage scope
+func EnsureAdministrator(r *http.Request) error {
+	apikey := GetApiKey(r)
+	if len(apikey) > 0 {
+		authorization, err := models.GetApiAuthorization(apikey)
+		if err != nil {
+			return err
+		}
+
+		if !authorization.IsAdministrator() {
+			return errors.New("api key is not from an admin user with manage scope")
+		}
+
+		return nil
+	}
+
 	system := models.ENV.MasterKey()
 	if len(system) == 0 {
-		return nil, errors.New("server is not allowed to use this method")
+		return errors.New("server is not allowed to use this method")
 	}
 
 	request := GetMasterKey(r)
 	if !strings.EqualFold(system, request) {
-		return nil, errors.New("dont even try to trick me, first strike")
+		return errors.New("dont even try to trick me, first strike")
 	}
 
-	return models.GetServerFirstAvailable()
+	return nil
+}
+
+// Scope required by an api request, declared at route registration, empty if not declared
+func GetRequiredScope(r *http.Request) string {
+	scope, _ := r.Context().Value(apiScopeKey{}).(string)
+	return scope
+}
+
+/*
+<summary>
+
+	Credentials for signalr connections, validated at negotiate and connect
+	Getting master key, then user api key, then bot token
+
+</summary>
+*/
+func GetSignalRAuthorization(r *http.Request) (*models.QpSignalRAuthorization, error) {
+	system := models.ENV.MasterKey()
+	masterkey := GetMasterKey(r)
+	if len(masterkey) > 0 {
+		if len(system) == 0 || !strings.EqualFold(system, masterkey) {
+			return nil, errors.New("invalid master key")
+		}
+
+		return &models.QpSignalRAuthorization{Master: true}, nil
+	}
+
+	apikey := GetApiKey(r)
+	if len(apikey) > 0 {
+		authorization, err := models.GetApiAuthorization(apikey)
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.QpSignalRAuthorization{ApiKey: authorization}, nil
+	}
+
+	token := GetToken(r)
+	if len(token) == 0 {
+		return nil, errors.New("missing token, api key or master key")
+	}
+
+	server, err := models.GetServerFromToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.QpSignalRAuthorization{Token: server.Token}, nil
 }
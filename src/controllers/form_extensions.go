@@ -147,6 +147,11 @@ func FormToggleController(w http.ResponseWriter, r *http.Request) {
 						err = models.ToggleCalls(webhook)
 						break
 					}
+				case "webhook-secret":
+					{
+						_, err = webhook.RotateSecret()
+						break
+					}
 				default:
 					{
 						err = fmt.Errorf("invalid webhook key: %s", key)
@@ -248,6 +253,9 @@ func FormDeleteController(w http.ResponseWriter, r *http.Request) {
 				destination = FormAccountEndpoint
 				logentry.Warnf("delete requested by form !")
 				err = models.WhatsappService.Delete(server)
+				if err == nil {
+					AuditRequest(r, models.AuditActionServerDelete, server.Token, server.Wid, nil, "delete requested by form")
+				}
 			}
 		case "webhook":
 			{
@@ -257,6 +265,7 @@ func FormDeleteController(w http.ResponseWriter, r *http.Request) {
 				affected, err = server.WebhookRemove(url)
 				if affected > 0 {
 					logentry.Infof("webhook delete requested by from, affected rows: %v", affected)
+					AuditRequest(r, models.AuditActionWebhookDelete, server.Token, url, nil, "delete requested by form")
 				}
 			}
 		default:
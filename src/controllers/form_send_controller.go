@@ -75,11 +75,13 @@ func controllerHttpPost(w http.ResponseWriter, r *http.Request) {
 	msg.Id = r.Form.Get("id")
 	_, err = server.SendMessage(msg)
 	if err != nil {
+		AuditSendFailed(r, server, msg, nil, err)
 		RespondServerError(server, w, err)
 		return
 	}
 
 	data.MessageId = msg.GetId()
+	AuditSend(r, server, msg, msg.GetId(), false, nil)
 
 	// Increment counter statistics
 	MessagesSent.Inc()
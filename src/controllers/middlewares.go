@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"context"
 	"net/http"
 	"strings"
+
+	models "github.com/nocodeleaks/quepasa/models"
 )
 
 func MiddlewareForNormalizePaths(next http.Handler) http.Handler {
@@ -14,3 +17,33 @@ func MiddlewareForNormalizePaths(next http.Handler) http.Handler {
 	}
 	return http.HandlerFunc(fn)
 }
+
+// Authenticates signalr negotiate and connect requests, credentials are kept on the connection context
+func MiddlewareForSignalRAuthentication(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		authorization, err := GetSignalRAuthorization(r)
+		if err != nil {
+			response := &models.QpResponse{}
+			response.ParseError(err)
+			RespondInterfaceCode(w, response, http.StatusUnauthorized)
+			return
+		}
+
+		ctx := models.WithSignalRAuthorization(r.Context(), authorization)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
+
+type apiScopeKey struct{}
+
+// Declares the api key scope required by a route, checked when getting the server
+func MiddlewareForApiScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), apiScopeKey{}, scope)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
@@ -67,6 +67,9 @@ func newRouter() chi.Router {
 	// Form routes, extra content
 	ServeForms(r)
 
+	// Event streams, without timeout
+	ServeStreams(r)
+
 	// SignalR
 	ServeSignalR(r)
 
@@ -126,6 +129,12 @@ func ServeAPI(r chi.Router) {
 	})
 }
 
+func ServeStreams(r chi.Router) {
+
+	// setting group, long running requests, no timeout
+	r.Group(RegisterAPIStreamControllers)
+}
+
 func ServeStaticContent(r chi.Router) {
 
 	// setting group
@@ -161,7 +170,11 @@ func ServeSignalR(r chi.Router) {
 	r.Group(func(r chi.Router) {
 		log.Debug("starting signalr service")
 
-		factory := signalr.UseHub(models.SignalRHub)
+		// connections must authenticate with master key, api key or bot token
+		r.Use(MiddlewareForSignalRAuthentication)
+
+		// new hub for each invocation, connections are kept on models.SignalRHub
+		factory := signalr.HubFactory(models.SignalRHubFactory)
 		//keepalive := signalr.KeepAliveInterval(2 * time.Second)
 		//timeout := signalr.ChanReceiveTimeout(1 * time.Hour)
 
@@ -0,0 +1,109 @@
+package library
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header sent on webhook posts when the webhook has a secret
+const WebhookSignatureHeader = "X-QUEPASA-SIGNATURE"
+
+// Default tolerance between signature timestamp and receiver clock
+const WebhookSignatureTolerance = 5 * time.Minute
+
+var ErrWebhookSignatureInvalidHeader = errors.New("invalid webhook signature header")
+var ErrWebhookSignatureExpired = errors.New("webhook signature timestamp out of tolerance")
+var ErrWebhookSignatureMismatch = errors.New("webhook signature mismatch")
+
+// Generates a new random secret for webhook signatures
+func GenerateWebhookSecret() (string, error) {
+	buffer := make([]byte, 32)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(buffer), nil
+}
+
+// Hex encoded HMAC-SHA256 of "{timestamp}.{body}" using the webhook secret
+func ComputeWebhookSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Value for signature header, formatted as: t={timestamp},v1={signature}
+func GenerateWebhookSignatureHeader(secret string, timestamp int64, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, ComputeWebhookSignature(secret, timestamp, body))
+}
+
+/*
+<summary>
+
+	Verifies a webhook signature header (X-QUEPASA-SIGNATURE) against the raw request body
+
+	Usage on receivers, before parsing the body:
+		body, _ := io.ReadAll(r.Body)
+		err := library.VerifyWebhookSignature(secret, r.Header.Get(library.WebhookSignatureHeader), body, library.WebhookSignatureTolerance)
+
+	* use the exact received bytes, any json re-encoding will change the signature
+	* tolerance protects against replay attacks, zero to skip timestamp validation
+
+</summary>
+*/
+func VerifyWebhookSignature(secret string, header string, body []byte, tolerance time.Duration) error {
+	var timestamp int64
+	var signatures []string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrWebhookSignatureInvalidHeader
+			}
+			timestamp = parsed
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrWebhookSignatureInvalidHeader
+	}
+
+	if tolerance > 0 {
+		diff := time.Since(time.Unix(timestamp, 0))
+		if diff < 0 {
+			diff = -diff
+		}
+
+		if diff > tolerance {
+			return ErrWebhookSignatureExpired
+		}
+	}
+
+	expected := ComputeWebhookSignature(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(expected), []byte(signature)) {
+			return nil
+		}
+	}
+
+	return ErrWebhookSignatureMismatch
+}
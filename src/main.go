@@ -51,6 +51,7 @@ func main() {
 		Broadcasts:   models.ENV.Broadcasts(),
 		ReadReceipts: models.ENV.ReadReceipts(),
 		Calls:        models.ENV.Calls(),
+		Newsletters:  models.ENV.Newsletters(),
 		ReadUpdate:   models.ENV.ReadUpdate(),
 		HistorySync:  models.ENV.HistorySync(),
 		Presence:     models.ENV.Presence(),
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `messages` (
+  `context` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  `id` VARCHAR (255) NOT NULL,
+  `chatid` VARCHAR (255) NOT NULL DEFAULT '',
+  `participant` VARCHAR (255) NOT NULL DEFAULT '',
+  `type` VARCHAR (50) NOT NULL DEFAULT '',
+  `status` VARCHAR (50) NOT NULL DEFAULT '',
+  `text` TEXT NOT NULL DEFAULT '',
+  `fromme` BOOLEAN NOT NULL DEFAULT FALSE,
+  `payload` BLOB DEFAULT NULL,
+  `content` BLOB DEFAULT NULL,
+  `info` BLOB DEFAULT NULL,
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT `messages_pkey` PRIMARY KEY (`context`, `id`)
+);
+
+CREATE INDEX IF NOT EXISTS `messages_timestamp` ON `messages` (`context`, `timestamp`);
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+  `id` CHAR (36) PRIMARY KEY NOT NULL,
+  `context` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  `url` VARCHAR (255) NOT NULL,
+  `messageid` VARCHAR (255) NOT NULL DEFAULT '',
+  `payload` BLOB DEFAULT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `nextattempt` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `lasterror` TEXT NOT NULL DEFAULT '',
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS `webhook_deliveries_nextattempt` ON `webhook_deliveries` (`context`, `nextattempt`);
+
+CREATE TABLE IF NOT EXISTS `webhook_deadletters` (
+  `id` CHAR (36) PRIMARY KEY NOT NULL,
+  `context` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  `url` VARCHAR (255) NOT NULL,
+  `messageid` VARCHAR (255) NOT NULL DEFAULT '',
+  `payload` BLOB DEFAULT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `nextattempt` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `lasterror` TEXT NOT NULL DEFAULT '',
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS `webhook_deadletters_url` ON `webhook_deadletters` (`context`, `url`);
@@ -0,0 +1 @@
+ALTER TABLE `webhooks` ADD COLUMN `secret` VARCHAR (255) NOT NULL DEFAULT '';
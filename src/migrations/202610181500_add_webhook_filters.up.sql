@@ -0,0 +1 @@
+ALTER TABLE `webhooks` ADD COLUMN `filters` BLOB DEFAULT NULL;
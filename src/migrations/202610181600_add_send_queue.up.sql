@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `send_queue` (
+  `id` VARCHAR (255) NOT NULL,
+  `context` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  `chatid` VARCHAR (255) NOT NULL,
+  `trackid` VARCHAR (255) NOT NULL DEFAULT '',
+  `message` BLOB DEFAULT NULL,
+  `content` BLOB DEFAULT NULL,
+  `ptt` BOOLEAN NOT NULL DEFAULT FALSE,
+  `status` VARCHAR (20) NOT NULL DEFAULT 'queued',
+  `lasterror` TEXT NOT NULL DEFAULT '',
+  `sendat` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`context`, `id`)
+);
+
+CREATE INDEX IF NOT EXISTS `send_queue_sendat` ON `send_queue` (`context`, `status`, `sendat`);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS `send_idempotency` (
+  `context` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  `requestkey` VARCHAR (255) NOT NULL,
+  `response` BLOB DEFAULT NULL,
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT `send_idempotency_pkey` PRIMARY KEY (`context`, `requestkey`)
+);
+
+CREATE INDEX IF NOT EXISTS `send_idempotency_timestamp` ON `send_idempotency` (`timestamp`);
@@ -0,0 +1 @@
+ALTER TABLE `servers` ADD COLUMN `callrules` BLOB DEFAULT NULL;
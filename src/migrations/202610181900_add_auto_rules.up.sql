@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `auto_rules` (
+  `id` VARCHAR (255) NOT NULL,
+  `context` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  `name` VARCHAR (255) NOT NULL DEFAULT '',
+  `priority` INT NOT NULL DEFAULT 0,
+  `disabled` BOOLEAN NOT NULL DEFAULT FALSE,
+  `conditions` BLOB DEFAULT NULL,
+  `action` BLOB DEFAULT NULL,
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`context`, `id`)
+);
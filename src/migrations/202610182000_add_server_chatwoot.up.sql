@@ -0,0 +1 @@
+ALTER TABLE `servers` ADD COLUMN `chatwoot` BLOB DEFAULT NULL;
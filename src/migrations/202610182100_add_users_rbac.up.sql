@@ -0,0 +1,19 @@
+ALTER TABLE `users` ADD COLUMN `role` VARCHAR (50) NOT NULL DEFAULT 'operator';
+
+CREATE TABLE IF NOT EXISTS `api_keys` (
+  `id` VARCHAR (255) PRIMARY KEY NOT NULL,
+  `username` CHAR (255) NOT NULL REFERENCES `users`(`username`),
+  `name` VARCHAR (255) NOT NULL DEFAULT '',
+  `hash` CHAR (64) UNIQUE NOT NULL,
+  `prefix` VARCHAR (50) NOT NULL DEFAULT '',
+  `scopes` VARCHAR (255) NOT NULL DEFAULT '',
+  `server` VARCHAR (100) NOT NULL DEFAULT '',
+  `expires` TIMESTAMP DEFAULT NULL,
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS `user_servers` (
+  `username` CHAR (255) NOT NULL REFERENCES `users`(`username`),
+  `token` CHAR (100) NOT NULL REFERENCES `servers`(`token`),
+  PRIMARY KEY (`username`, `token`)
+);
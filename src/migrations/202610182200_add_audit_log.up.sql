@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `audit_log` (
+  `id` VARCHAR (255) PRIMARY KEY NOT NULL,
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `actortype` VARCHAR (50) NOT NULL DEFAULT '',
+  `actor` VARCHAR (255) NOT NULL DEFAULT '',
+  `action` VARCHAR (100) NOT NULL,
+  `target` VARCHAR (100) NOT NULL DEFAULT '',
+  `resource` VARCHAR (255) NOT NULL DEFAULT '',
+  `digest` CHAR (64) NOT NULL DEFAULT '',
+  `details` TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS `audit_log_timestamp` ON `audit_log` (`timestamp`);
+CREATE INDEX IF NOT EXISTS `audit_log_target` ON `audit_log` (`target`, `timestamp`);
@@ -0,0 +1,3 @@
+ALTER TABLE `messages` ADD COLUMN `search` TEXT NOT NULL DEFAULT '';
+
+UPDATE `messages` SET `search` = `text`;
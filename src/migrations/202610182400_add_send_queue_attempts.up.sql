@@ -0,0 +1 @@
+ALTER TABLE `send_queue` ADD COLUMN `attempts` INT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE `webhooks` ADD COLUMN `presences` BOOLEAN DEFAULT NULL;
@@ -0,0 +1 @@
+ALTER TABLE `servers` ADD COLUMN `sendqueue` BLOB DEFAULT NULL;
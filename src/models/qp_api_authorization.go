@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrApiKeyInvalid = errors.New("invalid or expired api key")
+
+// Api request credentials from an user api key
+type QpApiAuthorization struct {
+	User *QpUser
+	Key  *QpApiKey
+}
+
+// Finds key and user from a plain api key
+func GetApiAuthorization(plain string) (*QpApiAuthorization, error) {
+	if !strings.HasPrefix(plain, ApiKeyPrefix) {
+		return nil, ErrApiKeyInvalid
+	}
+
+	db := WhatsappService.DB
+	key, err := db.ApiKeys.FindByHash(HashApiKey(plain))
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil || key.IsExpired(time.Now()) {
+		return nil, ErrApiKeyInvalid
+	}
+
+	user, err := db.Users.Find(key.Username)
+	if err != nil {
+		return nil, fmt.Errorf("api key user not found: %s", key.Username)
+	}
+
+	return &QpApiAuthorization{User: user, Key: key}, nil
+}
+
+// key scope, also allowed by user role
+func (source *QpApiAuthorization) HasScope(scope string) bool {
+	return source.Key.HasScope(scope) && UserRoleHasScope(source.User.GetRole(), scope)
+}
+
+// admins with manage scope can manage users, keys and use master key methods
+func (source *QpApiAuthorization) IsAdministrator() bool {
+	return source.User.IsAdmin() && source.HasScope(ApiScopeManage)
+}
+
+// bound server of key, all servers for admins, owned and assigned for others
+func (source *QpApiAuthorization) CanAccess(server *QpWhatsappServer) bool {
+	if len(source.Key.Server) > 0 && !strings.EqualFold(source.Key.Server, server.Token) {
+		return false
+	}
+
+	if source.User.IsAdmin() || server.User == source.User.Username {
+		return true
+	}
+
+	assigned, err := WhatsappService.DB.Users.GetServers(source.User.Username)
+	if err != nil {
+		return false
+	}
+
+	for _, token := range assigned {
+		if strings.EqualFold(token, server.Token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Gets an allowed server for the requested scope, token is optional for keys bound to a server
+func (source *QpApiAuthorization) GetServer(token string, scope string) (*QpWhatsappServer, error) {
+	if !source.HasScope(scope) {
+		return nil, fmt.Errorf("api key does not have scope: %s", scope)
+	}
+
+	if len(token) == 0 {
+		token = source.Key.Server
+	}
+
+	server, err := GetServerFromToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !source.CanAccess(server) {
+		return nil, fmt.Errorf("api key not allowed for server: %s", token)
+	}
+
+	return server, nil
+}
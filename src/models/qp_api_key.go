@@ -0,0 +1,108 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// prefix of generated api keys, helps secret scanners
+const ApiKeyPrefix = "qpk_"
+
+/*
+<summary>
+
+	Per user api key, only the sha256 hash is stored, plain key is returned once on creation
+	* optionally bound to a single server, requests without token use that server
+
+</summary>
+*/
+type QpApiKey struct {
+	Id       string `db:"id" json:"id"`
+	Username string `db:"username" json:"username"`
+	Name     string `db:"name" json:"name,omitempty"`
+
+	// sha256 of the plain key
+	Hash string `db:"hash" json:"-"`
+
+	// first chars of the plain key, for identification
+	Prefix string `db:"prefix" json:"prefix"`
+
+	// comma separated scopes, limited by user role
+	Scopes string `db:"scopes" json:"scopes"`
+
+	// optional server token
+	Server string `db:"server" json:"server,omitempty"`
+
+	Expires   *time.Time `db:"expires" json:"expires,omitempty"`
+	Timestamp time.Time  `db:"timestamp" json:"timestamp,omitempty"`
+}
+
+func (source *QpApiKey) GetScopes() (scopes []string) {
+	for _, item := range strings.Split(source.Scopes, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) > 0 {
+			scopes = append(scopes, item)
+		}
+	}
+	return
+}
+
+func (source *QpApiKey) HasScope(scope string) bool {
+	for _, item := range source.GetScopes() {
+		if item == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (source *QpApiKey) IsExpired(now time.Time) bool {
+	return source.Expires != nil && now.After(*source.Expires)
+}
+
+// formats scopes, all role scopes if empty
+func (source *QpApiKey) FormatScopes(role string) error {
+	scopes := source.GetScopes()
+	if len(scopes) == 0 {
+		scopes = UserRoleScopes[role]
+	}
+
+	for index, scope := range scopes {
+		scope = strings.ToLower(scope)
+		if !IsValidApiScope(scope) {
+			return fmt.Errorf("invalid scope: {%s}, try {%s}", scope, strings.Join(ApiScopes, ","))
+		}
+
+		if !UserRoleHasScope(role, scope) {
+			return fmt.Errorf("scope: %s, not allowed for role: %s", scope, role)
+		}
+
+		scopes[index] = scope
+	}
+
+	source.Scopes = strings.Join(scopes, ",")
+	return nil
+}
+
+// Generates a new random key, returning plain key and filling hash and prefix
+func (source *QpApiKey) Generate() (string, error) {
+	random := make([]byte, 24)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	plain := ApiKeyPrefix + hex.EncodeToString(random)
+	source.Hash = HashApiKey(plain)
+	source.Prefix = plain[:len(ApiKeyPrefix)+6]
+	return plain, nil
+}
+
+func HashApiKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
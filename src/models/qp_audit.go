@@ -0,0 +1,148 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Audit actor types
+const (
+	// server bot token
+	AuditActorToken = "token"
+
+	// MASTERKEY environment key
+	AuditActorMasterKey = "masterkey"
+
+	// user api key, actor is username and key prefix
+	AuditActorApiKey = "apikey"
+
+	// web form session user
+	AuditActorUser = "user"
+)
+
+// Audit actions
+const (
+	AuditActionServerCreate = "server.create"
+	AuditActionServerUpdate = "server.update"
+	AuditActionServerDelete = "server.delete"
+
+	// lifecycle, details has start, stop, restart or logout
+	AuditActionServerLifecycle = "server.lifecycle"
+
+	AuditActionWebhookUpdate = "webhook.update"
+	AuditActionWebhookDelete = "webhook.delete"
+	AuditActionWebhookSecret = "webhook.secret"
+
+	AuditActionMessageSend   = "message.send"
+	AuditActionMessageFailed = "message.failed"
+	AuditActionMessageEdit   = "message.edit"
+	AuditActionMessageRevoke = "message.revoke"
+	AuditActionMessageReact  = "message.react"
+
+	AuditActionUserCreate   = "user.create"
+	AuditActionUserUpdate   = "user.update"
+	AuditActionUserServer   = "user.server"
+	AuditActionApiKeyCreate = "apikey.create"
+	AuditActionApiKeyDelete = "apikey.delete"
+)
+
+/*
+<summary>
+
+	Record of an administrative or send action, payloads are kept only as sha256 digests
+
+</summary>
+*/
+type QpAuditEntry struct {
+	Id        string    `db:"id" json:"id"`
+	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+
+	// bot token actors are stored as hash
+	ActorType string `db:"actortype" json:"actortype"`
+	Actor     string `db:"actor" json:"actor"`
+
+	Action string `db:"action" json:"action"`
+
+	// server token hash, when applicable
+	Target string `db:"target" json:"target,omitempty"`
+
+	// affected item, as chat id, webhook url, username or key id
+	Resource string `db:"resource" json:"resource,omitempty"`
+
+	// sha256 of the request payload
+	Digest string `db:"digest" json:"digest,omitempty"`
+
+	// short description, never message contents
+	Details string `db:"details" json:"details,omitempty"`
+}
+
+// sha256 hex of raw bytes or json representation, empty for nil payloads
+func GetAuditDigest(payload interface{}) string {
+	var content []byte
+	switch value := payload.(type) {
+	case nil:
+		return ""
+	case []byte:
+		content = value
+	case string:
+		content = []byte(value)
+	default:
+		var err error
+		content, err = json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+	}
+
+	if len(content) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// prefix of token hashes, used to not hash twice
+const AuditTokenHashPrefix = "sha256:"
+
+// Short sha256 of a server token, audit entries never keeps raw tokens
+func GetAuditTokenHash(token string) string {
+	if len(token) == 0 || strings.HasPrefix(token, AuditTokenHashPrefix) {
+		return token
+	}
+
+	sum := sha256.Sum256([]byte(strings.ToLower(token)))
+	return AuditTokenHashPrefix + hex.EncodeToString(sum[:8])
+}
+
+// Persists an audit entry, errors are logged and never block the audited action
+func Audit(entry *QpAuditEntry) {
+	entry.Target = GetAuditTokenHash(entry.Target)
+	if entry.ActorType == AuditActorToken {
+		entry.Actor = GetAuditTokenHash(entry.Actor)
+	}
+
+	if len(entry.Id) == 0 {
+		entry.Id = uuid.New().String()
+	}
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	if WhatsappService == nil || WhatsappService.DB == nil || WhatsappService.DB.Audit == nil {
+		log.Warnf("audit database not available, action: %s, actor: %s", entry.Action, entry.Actor)
+		return
+	}
+
+	err := WhatsappService.DB.Audit.Add(entry)
+	if err != nil {
+		log.Errorf("error on saving audit entry, action: %s, actor: %s, cause: %s", entry.Action, entry.Actor, err.Error())
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Audit entries search, empty fields are ignored
+type QpAuditFilter struct {
+	ActorType string
+	Actor     string
+
+	// exact action, or prefix when ending with a dot, ex: "server."
+	Action string
+
+	Target   string
+	Resource string
+
+	Since *time.Time
+	Until *time.Time
+
+	Limit  uint
+	Offset uint
+}
@@ -0,0 +1,9 @@
+package models
+
+type QpAuditResponse struct {
+	QpResponse
+	Total   uint            `json:"total"`
+	Limit   uint            `json:"limit"`
+	Offset  uint            `json:"offset"`
+	Entries []*QpAuditEntry `json:"entries,omitempty"`
+}
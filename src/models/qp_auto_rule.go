@@ -0,0 +1,279 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Auto rule actions
+const (
+	// replies on the same chat, with templated text and optional media url
+	AutoRuleActionReply = "reply"
+
+	// forwards text and attachment to another chat
+	AutoRuleActionForward = "forward"
+
+	// posts the message to an url, same payload as webhooks
+	AutoRuleActionWebhook = "webhook"
+)
+
+// Message types evaluated when rule conditions does not specify types
+var AutoRuleDefaultTypes = []whatsapp.WhatsappMessageType{
+	whatsapp.TextMessageType,
+	whatsapp.ImageMessageType,
+	whatsapp.AudioMessageType,
+	whatsapp.VideoMessageType,
+	whatsapp.DocumentMessageType,
+	whatsapp.LocationMessageType,
+	whatsapp.ContactMessageType,
+}
+
+/*
+<summary>
+
+	Per server automation rule, evaluated on received messages by priority, first match is applied
+	* ex: {"name": "menu", "conditions": {"text": "(?i)^menu$"}, "action": {"type": "reply", "text": "hello {name}, ..."}}
+
+</summary>
+*/
+type QpAutoRule struct {
+	Id      string `db:"id" json:"id"`
+	Context string `db:"context" json:"-"`
+	Name    string `db:"name" json:"name,omitempty"`
+
+	// lower values are evaluated first
+	Priority int `db:"priority" json:"priority"`
+
+	// disabled rules are kept but not evaluated
+	Disabled bool `db:"disabled" json:"disabled,omitempty"`
+
+	Conditions *QpAutoRuleConditions `db:"conditions" json:"conditions,omitempty"`
+	Action     *QpAutoRuleAction     `db:"action" json:"action"`
+
+	Timestamp time.Time `db:"timestamp" json:"timestamp,omitempty"`
+}
+
+// All informed conditions must match, empty conditions matches every received message out of groups
+type QpAutoRuleConditions struct {
+	// glob patterns for chat ids, ex: "*@g.us"
+	Chats []string `json:"chats,omitempty"`
+
+	// message types, default text and media types
+	Types []string `json:"types,omitempty"`
+
+	// regular expression over message text, ex: "(?i)^(hi|hello)"
+	Text string `json:"text,omitempty"`
+
+	// days and time window
+	QpTimeWindow
+
+	// only when there is no previous message on this chat, cached or stored (if durable store is enabled)
+	FirstContact bool `json:"firstcontact,omitempty"`
+
+	expression *regexp.Regexp
+}
+
+type QpAutoRuleAction struct {
+	// reply, forward or webhook
+	Type string `json:"type"`
+
+	// templated text, placeholders: {chatid} {phone} {name} {text} {messageid}
+	// forward defaults to original text
+	Text string `json:"text,omitempty"`
+
+	// reply, optional media url, text is used as caption
+	Url string `json:"url,omitempty"`
+
+	// reply, quoting the received message
+	InReply bool `json:"inreply,omitempty"`
+
+	// forward, destination chat id
+	ChatId string `json:"chatid,omitempty"`
+
+	// webhook, destination url or broker
+	Webhook string `json:"webhook,omitempty"`
+}
+
+// reply and forward actions sends whatsapp messages, webhook only posts
+func (source *QpAutoRuleAction) SendsMessage() bool {
+	return source.Type == AutoRuleActionReply || source.Type == AutoRuleActionForward
+}
+
+// checks conditions and action, formatting destination chat
+func (source *QpAutoRule) Validate() (err error) {
+	if source.Action == nil {
+		return fmt.Errorf("action missing")
+	}
+
+	if source.Conditions != nil {
+		err = source.Conditions.Validate()
+		if err != nil {
+			return
+		}
+	}
+
+	return source.Action.Validate()
+}
+
+func (source *QpAutoRuleConditions) Validate() (err error) {
+	// receipts are never evaluated by rules
+	if item, found := FindInvalidEventType(source.Types); found {
+		return fmt.Errorf("invalid condition type: %s", item)
+	}
+
+	for _, item := range source.Chats {
+		if _, err := path.Match(item, ""); err != nil {
+			return fmt.Errorf("invalid condition chat pattern: %s", item)
+		}
+	}
+
+	if len(source.Text) > 0 {
+		source.expression, err = regexp.Compile(source.Text)
+		if err != nil {
+			return fmt.Errorf("invalid condition text expression: %s", err.Error())
+		}
+	}
+
+	err = source.QpTimeWindow.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid condition window: %s", err.Error())
+	}
+
+	return
+}
+
+func (source *QpAutoRuleAction) Validate() (err error) {
+	source.Type = strings.ToLower(strings.TrimSpace(source.Type))
+	switch source.Type {
+	case AutoRuleActionReply:
+		if len(strings.TrimSpace(source.Text)) == 0 && len(source.Url) == 0 {
+			return fmt.Errorf("reply action requires text or url")
+		}
+	case AutoRuleActionForward:
+		source.ChatId, err = whatsapp.FormatEndpoint(source.ChatId)
+		if err != nil {
+			return fmt.Errorf("invalid forward chat id: %s", err.Error())
+		}
+	case AutoRuleActionWebhook:
+		if !strings.HasPrefix(source.Webhook, "http") && !IsBrokerUrl(source.Webhook) {
+			return fmt.Errorf("invalid webhook url: %s", source.Webhook)
+		}
+	default:
+		return fmt.Errorf("invalid action type: {%s}, try {reply,forward,webhook}", source.Type)
+	}
+
+	return
+}
+
+// indicates that a received message matches all conditions, previous indicates earlier messages on the same chat
+func (source *QpAutoRuleConditions) Match(message *whatsapp.WhatsappMessage, now time.Time, previous bool) bool {
+	if len(source.Types) > 0 {
+		if !containsFold(source.Types, GetWebhookEventType(message)) {
+			return false
+		}
+	} else if !IsAutoRuleDefaultType(message.Type) {
+		return false
+	}
+
+	if len(source.Chats) > 0 && !matchAnyGlob(source.Chats, message.Chat.Id) {
+		return false
+	}
+
+	if len(source.Text) > 0 {
+		expression := source.expression
+		if expression == nil {
+			expression, _ = regexp.Compile(source.Text)
+		}
+
+		if expression == nil || !expression.MatchString(message.Text) {
+			return false
+		}
+	}
+
+	if !source.QpTimeWindow.Match(now) {
+		return false
+	}
+
+	if source.FirstContact && previous {
+		return false
+	}
+
+	return true
+}
+
+func IsAutoRuleDefaultType(messageType whatsapp.WhatsappMessageType) bool {
+	for _, item := range AutoRuleDefaultTypes {
+		if item == messageType {
+			return true
+		}
+	}
+	return false
+}
+
+// replaces placeholders with received message values
+func (source *QpAutoRuleAction) Render(text string, message *whatsapp.WhatsappMessage) string {
+	phone, _ := library.ExtractPhoneIfValid(message.Chat.Id)
+	replacer := strings.NewReplacer(
+		"{chatid}", message.Chat.Id,
+		"{phone}", phone,
+		"{name}", message.Chat.Title,
+		"{text}", message.Text,
+		"{messageid}", message.Id,
+	)
+	return replacer.Replace(text)
+}
+
+//#region DATABASE JSON COLUMNS
+
+func (source *QpAutoRuleConditions) Value() (driver.Value, error) {
+	if source == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(source)
+}
+
+func (source *QpAutoRuleConditions) Scan(value interface{}) error {
+	return scanJsonColumn(value, source)
+}
+
+func (source *QpAutoRuleAction) Value() (driver.Value, error) {
+	if source == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(source)
+}
+
+func (source *QpAutoRuleAction) Scan(value interface{}) error {
+	return scanJsonColumn(value, source)
+}
+
+func scanJsonColumn(value interface{}, destination interface{}) error {
+	switch content := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(content) == 0 {
+			return nil
+		}
+		return json.Unmarshal(content, destination)
+	case string:
+		if len(content) == 0 {
+			return nil
+		}
+		return json.Unmarshal([]byte(content), destination)
+	default:
+		return fmt.Errorf("invalid json column type: %T", value)
+	}
+}
+
+//#endregion
@@ -0,0 +1,294 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Auto reply and keyword rules engine, one per server, attached as an event handler
+type QpAutoRules struct {
+	library.LogStruct // logging
+
+	server *QpWhatsappServer
+	db     QpDataAutoRulesInterface
+
+	// cached rules, ordered by priority
+	rules []*QpAutoRule
+	mutex sync.RWMutex
+
+	// last reply or forward by chat id, avoids loops between automated numbers
+	cooldowns     map[string]time.Time
+	cooldownMutex sync.Mutex
+}
+
+func NewQpAutoRules(server *QpWhatsappServer, db QpDataAutoRulesInterface) *QpAutoRules {
+	return &QpAutoRules{
+		server:    server,
+		db:        db,
+		cooldowns: map[string]time.Time{},
+	}
+}
+
+// Reloads cached rules from database
+func (source *QpAutoRules) Load() error {
+	rules, err := source.db.FindAll(source.server.Token)
+	if err != nil {
+		return err
+	}
+
+	logentry := source.GetLogger()
+	for _, rule := range rules {
+		if rule.Conditions != nil {
+			err := rule.Conditions.Validate()
+			if err != nil {
+				logentry.Warnf("invalid auto rule: %s, conditions: %s", rule.Id, err.Error())
+			}
+		}
+	}
+
+	source.mutex.Lock()
+	source.rules = rules
+	source.mutex.Unlock()
+	return nil
+}
+
+// Cached rules, ordered by priority
+func (source *QpAutoRules) GetRules() []*QpAutoRule {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	return append([]*QpAutoRule{}, source.rules...)
+}
+
+func (source *QpAutoRules) GetById(id string) (*QpAutoRule, error) {
+	for _, rule := range source.GetRules() {
+		if rule.Id == id {
+			return rule, nil
+		}
+	}
+
+	return nil, fmt.Errorf("auto rule not found: %s", id)
+}
+
+// Validates and saves a rule, generating id for new ones
+func (source *QpAutoRules) Save(rule *QpAutoRule) (err error) {
+	err = rule.Validate()
+	if err != nil {
+		return
+	}
+
+	rule.Context = source.server.Token
+	if len(rule.Id) == 0 {
+		rule.Id = uuid.New().String()
+		rule.Timestamp = time.Now().UTC()
+		err = source.db.Add(rule)
+	} else {
+		var existing *QpAutoRule
+		existing, err = source.db.FindById(rule.Context, rule.Id)
+		if err == nil && existing == nil {
+			err = fmt.Errorf("auto rule not found: %s", rule.Id)
+		}
+
+		if err == nil {
+			rule.Timestamp = existing.Timestamp
+			err = source.db.Update(rule)
+		}
+	}
+
+	if err != nil {
+		return
+	}
+
+	return source.Load()
+}
+
+func (source *QpAutoRules) Remove(id string) (err error) {
+	affected, err := source.db.Remove(source.server.Token, id)
+	if err != nil {
+		return
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("auto rule not found: %s", id)
+	}
+
+	return source.Load()
+}
+
+// First enabled rule matching the message, nil if none
+// Group messages only match rules with chats conditions explicitly matching them
+func (source *QpAutoRules) Match(message *whatsapp.WhatsappMessage, now time.Time) *QpAutoRule {
+	var previous *bool
+	for _, rule := range source.GetRules() {
+		if rule.Disabled || rule.Action == nil {
+			continue
+		}
+
+		if message.FromGroup() && (rule.Conditions == nil || len(rule.Conditions.Chats) == 0) {
+			continue
+		}
+
+		if rule.Conditions == nil {
+			if IsAutoRuleDefaultType(message.Type) {
+				return rule
+			}
+			continue
+		}
+
+		if rule.Conditions.FirstContact && previous == nil && source.server.Handler != nil {
+			value := source.server.Handler.HasPrevious(message)
+			previous = &value
+		}
+
+		if rule.Conditions.Match(message, now, previous != nil && *previous) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+//#region IMPLEMENT WEBHOOK HANDLER INTERFACE
+
+// Evaluates rules for received messages, ignoring own, history, broadcasts and newsletters
+func (source *QpAutoRules) HandleWebHook(message *whatsapp.WhatsappMessage) {
+	if message.FromMe || message.FromInternal || message.FromHistory {
+		return
+	}
+
+	if message.FromBroadcast() || message.FromNewsletter() {
+		return
+	}
+
+	now := time.Now()
+	rule := source.Match(message, now)
+	if rule == nil {
+		return
+	}
+
+	logentry := source.GetLogger()
+	logentry = logentry.WithField(LogFields.MessageId, message.Id)
+	logentry = logentry.WithField(LogFields.ChatId, message.Chat.Id)
+
+	if rule.Action.SendsMessage() && !source.Cooldown(message.Chat.Id, now) {
+		logentry.Debugf("ignoring auto rule: %s (%s), chat on cooldown", rule.Id, rule.Name)
+		return
+	}
+
+	logentry.Infof("applying auto rule: %s (%s), action: %s", rule.Id, rule.Name, rule.Action.Type)
+
+	err := source.Execute(rule, message)
+	if err != nil {
+		logentry.Errorf("error on auto rule: %s, action: %s, cause: %s", rule.Id, rule.Action.Type, err.Error())
+	}
+}
+
+//#endregion
+
+// Registers an action on the chat, false if an earlier action is still on cooldown
+func (source *QpAutoRules) Cooldown(chatId string, now time.Time) bool {
+	cooldown := ENV.AutoRulesCooldown()
+	if cooldown == 0 {
+		return true
+	}
+
+	source.cooldownMutex.Lock()
+	defer source.cooldownMutex.Unlock()
+
+	if last, ok := source.cooldowns[chatId]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+
+	// removing expired entries
+	for key, last := range source.cooldowns {
+		if now.Sub(last) >= cooldown {
+			delete(source.cooldowns, key)
+		}
+	}
+
+	source.cooldowns[chatId] = now
+	return true
+}
+
+func (source *QpAutoRules) Execute(rule *QpAutoRule, message *whatsapp.WhatsappMessage) (err error) {
+	action := rule.Action
+	switch action.Type {
+	case AutoRuleActionReply:
+		return source.Reply(action, message)
+	case AutoRuleActionForward:
+		return source.Forward(action, message)
+	case AutoRuleActionWebhook:
+		webhook := &QpWebhook{Url: action.Webhook, Wid: source.server.Wid}
+		return webhook.Post(message)
+	default:
+		return fmt.Errorf("invalid action type: %s", action.Type)
+	}
+}
+
+// Replies on the same chat with templated text, and media from url if set
+func (source *QpAutoRules) Reply(action *QpAutoRuleAction, message *whatsapp.WhatsappMessage) (err error) {
+	request := &QpSendAnyRequest{Url: action.Url}
+	request.ChatId = message.Chat.Id
+	request.Text = action.Render(action.Text, message)
+	if action.InReply {
+		request.InReply = message.Id
+	}
+
+	if len(request.Url) > 0 {
+		err = request.GenerateUrlContent()
+		if err != nil {
+			return
+		}
+	}
+
+	reply, err := request.ToWhatsappMessage()
+	if err != nil {
+		return
+	}
+
+	att := request.ToWhatsappAttachment()
+	if att.Attach != nil {
+		reply.Attachment = att.Attach
+		reply.Type = whatsapp.GetMessageType(att.Attach)
+	} else {
+		reply.Type = whatsapp.TextMessageType
+	}
+
+	_, _, err = source.server.SendMessageOrEnqueue(reply, nil)
+	return
+}
+
+// Forwards text and attachment to another chat, text template defaults to original text
+func (source *QpAutoRules) Forward(action *QpAutoRuleAction, message *whatsapp.WhatsappMessage) (err error) {
+	text := message.Text
+	if len(strings.TrimSpace(action.Text)) > 0 {
+		text = action.Render(action.Text, message)
+	}
+
+	forward := &whatsapp.WhatsappMessage{
+		Chat:         whatsapp.WhatsappChat{Id: action.ChatId},
+		Type:         whatsapp.TextMessageType,
+		Text:         text,
+		FromMe:       true,
+		FromInternal: true,
+	}
+
+	if message.HasAttachment() {
+		attach, err := source.server.Download(message.Id, false)
+		if err != nil {
+			return err
+		}
+
+		forward.Attachment = attach
+		forward.Type = message.Type
+	}
+
+	_, _, err = source.server.SendMessageOrEnqueue(forward, nil)
+	return
+}
@@ -0,0 +1,12 @@
+package models
+
+type QpAutoRulesResponse struct {
+	QpResponse
+	Total int           `json:"total"`
+	Rules []*QpAutoRule `json:"rules,omitempty"`
+}
+
+type QpAutoRuleResponse struct {
+	QpResponse
+	Rule *QpAutoRule `json:"rule,omitempty"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// AMQP (RabbitMQ) sink, publishes persistent messages on exchange with routing key
+type QpBrokerSinkAmqp struct {
+	connection *amqp.Connection
+	channel    *amqp.Channel
+	mutex      sync.Mutex
+
+	Exchange   string
+	RoutingKey string
+}
+
+/*
+<summary>
+
+	Connects to an amqp broker
+	* exchange: optional, default exchange when empty
+	* routingkey: optional, defaults to queue or "quepasa"
+	* queue: optional, declared as durable if informed, useful with default exchange
+
+</summary>
+*/
+func NewQpBrokerSinkAmqp(parsed *url.URL) (*QpBrokerSinkAmqp, error) {
+	query := parsed.Query()
+	exchange := query.Get("exchange")
+	routingKey := query.Get("routingkey")
+	queue := query.Get("queue")
+
+	// removing our own parameters before dial
+	query.Del("exchange")
+	query.Del("routingkey")
+	query.Del("queue")
+
+	dialUrl := *parsed
+	dialUrl.RawQuery = query.Encode()
+
+	connection, err := amqp.Dial(dialUrl.String())
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := connection.Channel()
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	if len(queue) > 0 {
+		_, err = channel.QueueDeclare(queue, true, false, false, false, nil)
+		if err != nil {
+			connection.Close()
+			return nil, err
+		}
+
+		if len(routingKey) == 0 {
+			routingKey = queue
+		}
+	}
+
+	if len(routingKey) == 0 {
+		routingKey = BrokerSinkDefaultDestination
+	}
+
+	sink := &QpBrokerSinkAmqp{
+		connection: connection,
+		channel:    channel,
+		Exchange:   exchange,
+		RoutingKey: routingKey,
+	}
+	return sink, nil
+}
+
+func (source *QpBrokerSinkAmqp) Publish(ctx context.Context, key string, payload []byte, headers map[string]string) error {
+	table := amqp.Table{}
+	for name, value := range headers {
+		table[name] = value
+	}
+
+	publishing := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    key,
+		Headers:      table,
+		Body:         payload,
+	}
+
+	// channels should not be shared between concurrent publishers
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	return source.channel.PublishWithContext(ctx, source.Exchange, source.RoutingKey, false, false, publishing)
+}
+
+func (source *QpBrokerSinkAmqp) Close() error {
+	return source.connection.Close()
+}
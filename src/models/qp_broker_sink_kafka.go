@@ -0,0 +1,51 @@
+package models
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Kafka sink, writes on topic from url path, message id as key
+type QpBrokerSinkKafka struct {
+	writer *kafka.Writer
+}
+
+// Creates a writer for comma separated brokers on url host, topic from url path, default "quepasa"
+func NewQpBrokerSinkKafka(parsed *url.URL) (*QpBrokerSinkKafka, error) {
+	brokers := strings.Split(parsed.Host, ",")
+
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Topic:                  getBrokerDestination(parsed),
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireOne,
+		AllowAutoTopicCreation: true,
+
+		// publishing one by one, avoid waiting for batches
+		BatchTimeout: 10 * time.Millisecond,
+	}
+
+	sink := &QpBrokerSinkKafka{writer: writer}
+	return sink, nil
+}
+
+func (source *QpBrokerSinkKafka) Publish(ctx context.Context, key string, payload []byte, headers map[string]string) error {
+	msg := kafka.Message{
+		Key:   []byte(key),
+		Value: payload,
+	}
+
+	for name, value := range headers {
+		msg.Headers = append(msg.Headers, kafka.Header{Key: name, Value: []byte(value)})
+	}
+
+	return source.writer.WriteMessages(ctx, msg)
+}
+
+func (source *QpBrokerSinkKafka) Close() error {
+	return source.writer.Close()
+}
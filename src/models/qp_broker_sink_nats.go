@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+)
+
+// NATS sink, publishes on subject from url path
+type QpBrokerSinkNats struct {
+	connection *nats.Conn
+
+	Subject string
+}
+
+// Connects to a nats server, subject from url path, default "quepasa"
+func NewQpBrokerSinkNats(parsed *url.URL) (*QpBrokerSinkNats, error) {
+	subject := getBrokerDestination(parsed)
+
+	// nats client does not accept path on server url
+	serverUrl := *parsed
+	serverUrl.Path = ""
+	serverUrl.RawPath = ""
+	serverUrl.RawQuery = ""
+
+	// nats client uses tls scheme for secure connections
+	if strings.EqualFold(serverUrl.Scheme, "natss") {
+		serverUrl.Scheme = "tls"
+	}
+
+	connection, err := nats.Connect(serverUrl.String(), nats.Name("quepasa"))
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &QpBrokerSinkNats{
+		connection: connection,
+		Subject:    subject,
+	}
+	return sink, nil
+}
+
+func (source *QpBrokerSinkNats) Publish(ctx context.Context, key string, payload []byte, headers map[string]string) error {
+	msg := nats.NewMsg(source.Subject)
+	msg.Data = payload
+
+	// jetstream deduplicates by msg id, the same message id is published again on receipts, edits and status updates
+	msg.Header.Set("Nats-Msg-Id", uuid.New().String())
+	for name, value := range headers {
+		msg.Header.Set(name, value)
+	}
+
+	err := source.connection.PublishMsg(msg)
+	if err != nil {
+		return err
+	}
+
+	// ensures that server received the message
+	return source.connection.FlushWithContext(ctx)
+}
+
+func (source *QpBrokerSinkNats) Close() error {
+	source.connection.Close()
+	return nil
+}
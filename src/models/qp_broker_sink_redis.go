@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis Streams sink, appends entries with payload and headers as fields
+type QpBrokerSinkRedis struct {
+	client *redis.Client
+
+	Stream string
+	MaxLen int64
+}
+
+/*
+<summary>
+
+	Connects to a redis server, database from url path
+	* stream: optional, default "quepasa"
+	* maxlen: optional, approximated stream trimming, zero for unlimited
+
+</summary>
+*/
+func NewQpBrokerSinkRedis(parsed *url.URL) (*QpBrokerSinkRedis, error) {
+	query := parsed.Query()
+	stream := query.Get("stream")
+	if len(stream) == 0 {
+		stream = BrokerSinkDefaultDestination
+	}
+
+	var maxlen int64
+	if value := query.Get("maxlen"); len(value) > 0 {
+		parsedMaxLen, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		maxlen = parsedMaxLen
+	}
+
+	// redis client fails on unknown parameters
+	query.Del("stream")
+	query.Del("maxlen")
+
+	clientUrl := *parsed
+	clientUrl.RawQuery = query.Encode()
+
+	options, err := redis.ParseURL(clientUrl.String())
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(options)
+
+	sink := &QpBrokerSinkRedis{
+		client: client,
+		Stream: stream,
+		MaxLen: maxlen,
+	}
+	return sink, nil
+}
+
+func (source *QpBrokerSinkRedis) Publish(ctx context.Context, key string, payload []byte, headers map[string]string) error {
+	values := map[string]interface{}{
+		"id":      key,
+		"payload": payload,
+	}
+
+	for name, value := range headers {
+		values[name] = value
+	}
+
+	args := &redis.XAddArgs{
+		Stream: source.Stream,
+		MaxLen: source.MaxLen,
+		Approx: source.MaxLen > 0,
+		Values: values,
+	}
+
+	return source.client.XAdd(ctx, args).Err()
+}
+
+func (source *QpBrokerSinkRedis) Close() error {
+	return source.client.Close()
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Call rule actions
+const (
+	// rejects the call, optionally replying
+	CallRuleActionReject = "reject"
+
+	// keeps ringing, only replies
+	CallRuleActionReply = "reply"
+)
+
+/*
+<summary>
+
+	Per server rules for incoming calls, first matching rule is applied on call offers
+	* ex: reject and reply outside business hours
+	{"rules": [{"action": "reject", "reply": "we are closed", "days": [1,2,3,4,5], "start": "09:00", "end": "18:00", "outside": true, "timezone": "America/Sao_Paulo"}]}
+
+</summary>
+*/
+type QpCallRules struct {
+	Rules []*QpCallRule `json:"rules,omitempty"`
+}
+
+type QpCallRule struct {
+	// reject or reply
+	Action string `json:"action"`
+
+	// optional text sent to the caller
+	Reply string `json:"reply,omitempty"`
+
+	// days and time window
+	QpTimeWindow
+
+	// optional glob patterns for caller chat ids, empty for everyone
+	Chats []string `json:"chats,omitempty"`
+
+	// optional, only voice (false) or video (true) calls
+	Video *bool `json:"video,omitempty"`
+}
+
+func (source *QpCallRules) IsEmpty() bool {
+	return source == nil || len(source.Rules) == 0
+}
+
+// checks actions, days, windows, time zones and patterns
+func (source *QpCallRules) Validate() error {
+	if source == nil {
+		return nil
+	}
+
+	for index, rule := range source.Rules {
+		if rule == nil {
+			return fmt.Errorf("invalid call rule at %v: empty", index)
+		}
+
+		rule.Action = strings.ToLower(strings.TrimSpace(rule.Action))
+		if rule.Action != CallRuleActionReject && rule.Action != CallRuleActionReply {
+			return fmt.Errorf("invalid call rule action at %v: {%s}, try {reject,reply}", index, rule.Action)
+		}
+
+		if rule.Action == CallRuleActionReply && len(strings.TrimSpace(rule.Reply)) == 0 {
+			return fmt.Errorf("invalid call rule at %v: reply text missing", index)
+		}
+
+		if err := rule.QpTimeWindow.Validate(); err != nil {
+			return fmt.Errorf("invalid call rule at %v: %s", index, err.Error())
+		}
+
+		for _, item := range rule.Chats {
+			if _, err := path.Match(item, ""); err != nil {
+				return fmt.Errorf("invalid call rule chat pattern at %v: %s", index, item)
+			}
+		}
+	}
+
+	return nil
+}
+
+// first rule matching the incoming call at the given time, nil if none
+func (source *QpCallRules) Match(message *whatsapp.WhatsappMessage, now time.Time) *QpCallRule {
+	if source.IsEmpty() || message.Call == nil {
+		return nil
+	}
+
+	for _, rule := range source.Rules {
+		if rule.Match(message, now) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+func (source *QpCallRule) Match(message *whatsapp.WhatsappMessage, now time.Time) bool {
+	if source.Video != nil && *source.Video != message.Call.Video {
+		return false
+	}
+
+	if len(source.Chats) > 0 && !matchAnyGlob(source.Chats, message.Chat.Id) {
+		return false
+	}
+
+	return source.QpTimeWindow.Match(now)
+}
+
+//#region DATABASE JSON COLUMN
+
+func (source *QpCallRules) Value() (driver.Value, error) {
+	if source.IsEmpty() {
+		return nil, nil
+	}
+
+	return json.Marshal(source)
+}
+
+func (source *QpCallRules) Scan(value interface{}) error {
+	switch content := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(content) == 0 {
+			return nil
+		}
+		return json.Unmarshal(content, source)
+	case string:
+		if len(content) == 0 {
+			return nil
+		}
+		return json.Unmarshal([]byte(content), source)
+	default:
+		return fmt.Errorf("invalid call rules type: %T", value)
+	}
+}
+
+//#endregion
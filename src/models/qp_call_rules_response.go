@@ -0,0 +1,6 @@
+package models
+
+type QpCallRulesResponse struct {
+	QpResponse
+	CallRules *QpCallRules `json:"callrules,omitempty"`
+}
@@ -0,0 +1,236 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type QpChatwootContactInbox struct {
+	SourceId string `json:"source_id"`
+	Inbox    *struct {
+		Id int `json:"id"`
+	} `json:"inbox,omitempty"`
+}
+
+type QpChatwootContact struct {
+	Id             int                      `json:"id"`
+	Name           string                   `json:"name,omitempty"`
+	Identifier     string                   `json:"identifier,omitempty"`
+	PhoneNumber    string                   `json:"phone_number,omitempty"`
+	ContactInboxes []QpChatwootContactInbox `json:"contact_inboxes,omitempty"`
+}
+
+// source id of this contact on the inbox, empty if not linked
+func (source *QpChatwootContact) GetSourceId(inbox int) string {
+	for _, item := range source.ContactInboxes {
+		if item.Inbox != nil && item.Inbox.Id == inbox {
+			return item.SourceId
+		}
+	}
+	return ""
+}
+
+type QpChatwootConversation struct {
+	Id      int    `json:"id"`
+	InboxId int    `json:"inbox_id"`
+	Status  string `json:"status,omitempty"`
+}
+
+type QpChatwootMessage struct {
+	Id int `json:"id"`
+}
+
+// Minimal Chatwoot application api client
+type QpChatwootClient struct {
+	Config *QpChatwootConfig
+}
+
+func (source *QpChatwootClient) endpoint(path string) string {
+	return fmt.Sprintf("%s/api/v1/accounts/%v/%s", source.Config.Url, source.Config.Account, path)
+}
+
+func (source *QpChatwootClient) do(method string, path string, contentType string, body io.Reader, result interface{}) (err error) {
+	req, err := http.NewRequest(method, source.endpoint(path), body)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("User-Agent", "Quepasa")
+	req.Header.Set("api_access_token", source.Config.Token)
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	client := &http.Client{}
+	client.Timeout = ENV.WebhookTimeout()
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("chatwoot %s %s: %s, %s", method, path, resp.Status, string(content))
+	}
+
+	if result == nil {
+		return
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (source *QpChatwootClient) doJson(method string, path string, payload interface{}, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		content, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(content)
+	}
+
+	return source.do(method, path, "application/json", body, result)
+}
+
+// Finds a contact by exact identifier, nil if not found
+func (source *QpChatwootClient) SearchContact(identifier string) (*QpChatwootContact, error) {
+	result := &struct {
+		Payload []*QpChatwootContact `json:"payload"`
+	}{}
+
+	err := source.doJson(http.MethodGet, "contacts/search?q="+url.QueryEscape(identifier), nil, result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contact := range result.Payload {
+		if contact.Identifier == identifier {
+			return contact, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Creates a contact linked to the configured inbox, returning the inbox source id
+func (source *QpChatwootClient) CreateContact(identifier string, name string, phone string) (*QpChatwootContact, string, error) {
+	payload := map[string]interface{}{
+		"inbox_id":   source.Config.Inbox,
+		"identifier": identifier,
+		"name":       name,
+	}
+
+	if len(phone) > 0 {
+		payload["phone_number"] = phone
+	}
+
+	result := &struct {
+		Payload struct {
+			Contact      *QpChatwootContact     `json:"contact"`
+			ContactInbox QpChatwootContactInbox `json:"contact_inbox"`
+		} `json:"payload"`
+	}{}
+
+	err := source.doJson(http.MethodPost, "contacts", payload, result)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if result.Payload.Contact == nil {
+		return nil, "", fmt.Errorf("chatwoot contact not returned for: %s", identifier)
+	}
+
+	return result.Payload.Contact, result.Payload.ContactInbox.SourceId, nil
+}
+
+// Links an existing contact to the configured inbox, returning the source id
+func (source *QpChatwootClient) CreateContactInbox(contact int, sourceId string) (string, error) {
+	payload := map[string]interface{}{
+		"inbox_id":  source.Config.Inbox,
+		"source_id": sourceId,
+	}
+
+	result := &QpChatwootContactInbox{}
+	err := source.doJson(http.MethodPost, "contacts/"+strconv.Itoa(contact)+"/contact_inboxes", payload, result)
+	return result.SourceId, err
+}
+
+func (source *QpChatwootClient) GetConversations(contact int) ([]*QpChatwootConversation, error) {
+	result := &struct {
+		Payload []*QpChatwootConversation `json:"payload"`
+	}{}
+
+	err := source.doJson(http.MethodGet, "contacts/"+strconv.Itoa(contact)+"/conversations", nil, result)
+	return result.Payload, err
+}
+
+func (source *QpChatwootClient) CreateConversation(contact int, sourceId string) (*QpChatwootConversation, error) {
+	payload := map[string]interface{}{
+		"inbox_id":   source.Config.Inbox,
+		"contact_id": contact,
+		"source_id":  sourceId,
+		"status":     "open",
+	}
+
+	result := &QpChatwootConversation{}
+	err := source.doJson(http.MethodPost, "conversations", payload, result)
+	return result, err
+}
+
+// Creates an incoming or outgoing message, with optional attachment, marked as sent by quepasa
+func (source *QpChatwootClient) CreateMessage(conversation int, content string, messageType string, attach *whatsapp.WhatsappAttachment) (*QpChatwootMessage, error) {
+	path := "conversations/" + strconv.Itoa(conversation) + "/messages"
+	result := &QpChatwootMessage{}
+
+	if attach == nil || !attach.HasContent() {
+		payload := map[string]interface{}{
+			"content":            content,
+			"message_type":       messageType,
+			"private":            false,
+			"content_attributes": map[string]interface{}{ChatwootMessageAttribute: true},
+		}
+
+		err := source.doJson(http.MethodPost, path, payload, result)
+		return result, err
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("content", content)
+	writer.WriteField("message_type", messageType)
+	writer.WriteField("private", "false")
+	writer.WriteField("content_attributes", fmt.Sprintf(`{"%s": true}`, ChatwootMessageAttribute))
+
+	filename := attach.FileName
+	if len(filename) == 0 {
+		filename = "attachment"
+	}
+
+	part, err := writer.CreateFormFile("attachments[]", filename)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = part.Write(*attach.GetContent())
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = source.do(http.MethodPost, path, writer.FormDataContentType(), body, result)
+	return result, err
+}
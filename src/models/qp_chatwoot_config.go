@@ -0,0 +1,120 @@
+package models
+
+import (
+	"crypto/subtle"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+/*
+<summary>
+
+	Per server Chatwoot channel settings, received messages are mirrored to an API inbox
+	and agent replies are received on POST "/chatwoot/webhook/{secret}"
+	* ex: {"url": "https://chatwoot.example.com", "account": 1, "inbox": 2, "token": "{api access token}"}
+
+</summary>
+*/
+type QpChatwootConfig struct {
+	// chatwoot base url
+	Url string `json:"url"`
+
+	Account int `json:"account"`
+
+	// api channel inbox id
+	Inbox int `json:"inbox"`
+
+	// user or agent bot access token, never returned on responses
+	Token string `json:"token,omitempty"`
+
+	// generated secret for the inbox webhook url, the bot token is never used there
+	Secret string `json:"secret,omitempty"`
+
+	// also mirror group messages, one conversation per group
+	Groups bool `json:"groups,omitempty"`
+
+	// prefixes agent replies with the agent name
+	Sign bool `json:"sign,omitempty"`
+
+	// keeps settings without mirroring
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+func (source *QpChatwootConfig) Validate() error {
+	if source == nil {
+		return nil
+	}
+
+	source.Url = strings.TrimRight(strings.TrimSpace(source.Url), "/")
+	parsed, err := url.Parse(source.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || len(parsed.Host) == 0 {
+		return fmt.Errorf("invalid chatwoot url: %s", source.Url)
+	}
+
+	if source.Account <= 0 {
+		return fmt.Errorf("invalid chatwoot account id: %v", source.Account)
+	}
+
+	if source.Inbox <= 0 {
+		return fmt.Errorf("invalid chatwoot inbox id: %v", source.Inbox)
+	}
+
+	if len(strings.TrimSpace(source.Token)) == 0 {
+		return fmt.Errorf("chatwoot access token missing")
+	}
+
+	return nil
+}
+
+// copy without access token, for responses
+func (source *QpChatwootConfig) Redacted() *QpChatwootConfig {
+	if source == nil {
+		return nil
+	}
+
+	redacted := *source
+	redacted.Token = ""
+	return &redacted
+}
+
+// path to set as inbox webhook url, after quepasa url
+func (source *QpChatwootConfig) GetWebhookPath() string {
+	if source == nil || len(source.Secret) == 0 {
+		return ""
+	}
+
+	return "/chatwoot/webhook/" + source.Secret
+}
+
+// compares with the webhook url secret in constant time
+func (source *QpChatwootConfig) MatchSecret(secret string) bool {
+	if source == nil || len(source.Secret) == 0 {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(source.Secret), []byte(secret)) == 1
+}
+
+// indicates that messages should be mirrored
+func (source *QpChatwootConfig) IsActive() bool {
+	return source != nil && !source.Disabled
+}
+
+//#region DATABASE JSON COLUMN
+
+func (source *QpChatwootConfig) Value() (driver.Value, error) {
+	if source == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(source)
+}
+
+func (source *QpChatwootConfig) Scan(value interface{}) error {
+	return scanJsonColumn(value, source)
+}
+
+//#endregion
@@ -0,0 +1,319 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// track id of messages sent from chatwoot agent replies, never mirrored back
+const ChatwootTrackId = "chatwoot"
+
+// content attribute of chatwoot messages created by quepasa, never sent back
+const ChatwootMessageAttribute = "quepasa"
+
+// keeps ids of chatwoot messages created by quepasa
+const ChatwootSentRetention = time.Hour
+
+// Message types mirrored to chatwoot
+var ChatwootMessageTypes = []whatsapp.WhatsappMessageType{
+	whatsapp.TextMessageType,
+	whatsapp.ImageMessageType,
+	whatsapp.AudioMessageType,
+	whatsapp.VideoMessageType,
+	whatsapp.DocumentMessageType,
+	whatsapp.LocationMessageType,
+	whatsapp.ContactMessageType,
+}
+
+// Chatwoot channel sink, attached as an event handler, also receives agent replies
+type QpChatwootHandler struct {
+	library.LogStruct // logging
+
+	server *QpWhatsappServer
+
+	// chat id => chatwoot conversation id
+	conversations sync.Map
+
+	// chatwoot message id => creation time
+	sent sync.Map
+
+	// avoid duplicated contacts and conversations
+	syncConversations sync.Mutex
+}
+
+func NewQpChatwootHandler(server *QpWhatsappServer) *QpChatwootHandler {
+	return &QpChatwootHandler{server: server}
+}
+
+// forget cached conversations, used when settings change
+func (source *QpChatwootHandler) Reset() {
+	source.conversations.Range(func(key, value any) bool {
+		source.conversations.Delete(key)
+		return true
+	})
+}
+
+func (source *QpChatwootHandler) ShouldMirror(config *QpChatwootConfig, message *whatsapp.WhatsappMessage) bool {
+	if !config.IsActive() {
+		return false
+	}
+
+	if message.FromHistory || message.TrackId == ChatwootTrackId {
+		return false
+	}
+
+	if message.FromBroadcast() || message.FromNewsletter() {
+		return false
+	}
+
+	if message.FromGroup() && !config.Groups {
+		return false
+	}
+
+	for _, item := range ChatwootMessageTypes {
+		if item == message.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+//#region IMPLEMENT WEBHOOK HANDLER INTERFACE
+
+func (source *QpChatwootHandler) HandleWebHook(message *whatsapp.WhatsappMessage) {
+	config := source.server.GetChatwoot()
+	if !source.ShouldMirror(config, message) {
+		return
+	}
+
+	logentry := source.GetLogger()
+	logentry = logentry.WithField(LogFields.MessageId, message.Id)
+	logentry = logentry.WithField(LogFields.ChatId, message.Chat.Id)
+
+	err := source.Mirror(config, message)
+	if err != nil {
+		logentry.Errorf("error on mirror message to chatwoot: %s", err.Error())
+	} else {
+		logentry.Debug("message mirrored to chatwoot")
+	}
+}
+
+//#endregion
+
+// Posts a message to the chat conversation, creating contact and conversation if needed
+func (source *QpChatwootHandler) Mirror(config *QpChatwootConfig, message *whatsapp.WhatsappMessage) (err error) {
+	client := &QpChatwootClient{Config: config}
+
+	conversation, err := source.GetConversation(client, message.Chat)
+	if err != nil {
+		return
+	}
+
+	content := message.Text
+	if message.FromGroup() && message.Participant != nil && !message.FromMe {
+		participant := message.Participant.Title
+		if len(participant) == 0 {
+			participant = message.Participant.GetPhone()
+		}
+		content = fmt.Sprintf("**%s:**\n%s", participant, content)
+	}
+
+	var attach *whatsapp.WhatsappAttachment
+	if message.Type == whatsapp.LocationMessageType && message.Attachment != nil {
+		location := fmt.Sprintf("https://www.google.com/maps?q=%v,%v", message.Attachment.Latitude, message.Attachment.Longitude)
+		content = strings.TrimSpace(content + "\n" + location)
+	} else if message.HasAttachment() {
+		attach, err = source.server.Download(message.Id, false)
+		if err != nil {
+			source.GetLogger().Warnf("error on download attachment for chatwoot, msg: %s, cause: %s", message.Id, err.Error())
+			attach = nil
+			if len(message.Attachment.Url) > 0 {
+				content = strings.TrimSpace(content + "\n" + message.Attachment.Url)
+			}
+		}
+	}
+
+	messageType := "incoming"
+	if message.FromMe {
+		messageType = "outgoing"
+	}
+
+	created, err := client.CreateMessage(conversation, content, messageType, attach)
+	if err != nil {
+		// conversation may be deleted, try again on next message
+		source.conversations.Delete(message.Chat.Id)
+		return
+	}
+
+	source.MarkSent(created.Id)
+	return
+}
+
+// Gets cached conversation id or finds/creates contact and conversation on the inbox
+func (source *QpChatwootHandler) GetConversation(client *QpChatwootClient, chat whatsapp.WhatsappChat) (int, error) {
+	if value, ok := source.conversations.Load(chat.Id); ok {
+		return value.(int), nil
+	}
+
+	source.syncConversations.Lock()
+	defer source.syncConversations.Unlock()
+
+	// created while waiting
+	if value, ok := source.conversations.Load(chat.Id); ok {
+		return value.(int), nil
+	}
+
+	inbox := client.Config.Inbox
+	contact, err := client.SearchContact(chat.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	var sourceId string
+	if contact == nil {
+		name := chat.Title
+		phone := chat.GetPhone()
+		if len(name) == 0 {
+			name = phone
+		}
+		if len(name) == 0 {
+			name = chat.Id
+		}
+
+		contact, sourceId, err = client.CreateContact(chat.Id, name, phone)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		sourceId = contact.GetSourceId(inbox)
+	}
+
+	if len(sourceId) == 0 {
+		sourceId, err = client.CreateContactInbox(contact.Id, uuid.New().String())
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	conversations, err := client.GetConversations(contact.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	var conversation int
+	for _, item := range conversations {
+		if item.InboxId == inbox && item.Status != "resolved" {
+			conversation = item.Id
+			break
+		}
+	}
+
+	if conversation == 0 {
+		created, err := client.CreateConversation(contact.Id, sourceId)
+		if err != nil {
+			return 0, err
+		}
+		conversation = created.Id
+	}
+
+	source.conversations.Store(chat.Id, conversation)
+	return conversation, nil
+}
+
+// remembers chatwoot messages created by quepasa, discarding expired ones
+func (source *QpChatwootHandler) MarkSent(id int) {
+	now := time.Now()
+	source.sent.Range(func(key, value any) bool {
+		if now.Sub(value.(time.Time)) > ChatwootSentRetention {
+			source.sent.Delete(key)
+		}
+		return true
+	})
+
+	source.sent.Store(id, now)
+}
+
+func (source *QpChatwootHandler) IsSent(id int) bool {
+	_, ok := source.sent.Load(id)
+	return ok
+}
+
+// Sends agent replies from chatwoot webhook to whatsapp, ignoring private notes and own messages
+func (source *QpChatwootHandler) Receive(payload *QpChatwootWebhookPayload) (sent int, err error) {
+	config := source.server.GetChatwoot()
+	if !config.IsActive() {
+		err = fmt.Errorf("chatwoot integration not active for this server")
+		return
+	}
+
+	if !payload.IsAgentReply() || source.IsSent(payload.Id) {
+		return
+	}
+
+	chatId, err := payload.GetChatId()
+	if err != nil {
+		return
+	}
+
+	text := payload.Content
+	if config.Sign && payload.Sender != nil && len(payload.Sender.Name) > 0 {
+		text = fmt.Sprintf("*%s:*\n%s", payload.Sender.Name, text)
+	}
+
+	// text only reply
+	if len(payload.Attachments) == 0 {
+		err = source.Send(&QpSendAnyRequest{}, chatId, text)
+		if err != nil {
+			return
+		}
+		return 1, nil
+	}
+
+	// one message per attachment, text as caption of the first
+	for _, attachment := range payload.Attachments {
+		request := &QpSendAnyRequest{Url: attachment.DataUrl}
+		err = request.GenerateUrlContent()
+		if err != nil {
+			return
+		}
+
+		err = source.Send(request, chatId, text)
+		if err != nil {
+			return
+		}
+
+		text = ""
+		sent++
+	}
+
+	return
+}
+
+func (source *QpChatwootHandler) Send(request *QpSendAnyRequest, chatId string, text string) (err error) {
+	request.ChatId = chatId
+	request.Text = text
+	request.TrackId = ChatwootTrackId
+
+	message, err := request.ToWhatsappMessage()
+	if err != nil {
+		return
+	}
+
+	att := request.ToWhatsappAttachment()
+	if att.Attach != nil {
+		message.Attachment = att.Attach
+		message.Type = whatsapp.GetMessageType(att.Attach)
+	} else {
+		message.Type = whatsapp.TextMessageType
+	}
+
+	_, _, err = source.server.SendMessageOrEnqueue(message, nil)
+	return
+}
@@ -0,0 +1,9 @@
+package models
+
+type QpChatwootResponse struct {
+	QpResponse
+	Chatwoot *QpChatwootConfig `json:"chatwoot,omitempty"`
+
+	// inbox webhook url path, after quepasa url
+	Webhook string `json:"webhook,omitempty"`
+}
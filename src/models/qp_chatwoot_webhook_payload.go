@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Chatwoot api inbox webhook event, only used fields
+type QpChatwootWebhookPayload struct {
+	Event             string                 `json:"event"`
+	Id                int                    `json:"id"`
+	Content           string                 `json:"content"`
+	MessageType       string                 `json:"message_type"`
+	Private           bool                   `json:"private"`
+	ContentAttributes map[string]interface{} `json:"content_attributes,omitempty"`
+
+	Attachments []struct {
+		DataUrl  string `json:"data_url"`
+		FileType string `json:"file_type"`
+	} `json:"attachments,omitempty"`
+
+	Sender *struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"sender,omitempty"`
+
+	Conversation *struct {
+		Id   int `json:"id"`
+		Meta struct {
+			Sender QpChatwootContact `json:"sender"`
+		} `json:"meta"`
+	} `json:"conversation,omitempty"`
+}
+
+// public outgoing message created on chatwoot, not by quepasa
+func (source *QpChatwootWebhookPayload) IsAgentReply() bool {
+	if source.Event != "message_created" || source.MessageType != "outgoing" || source.Private {
+		return false
+	}
+
+	if _, ok := source.ContentAttributes[ChatwootMessageAttribute]; ok {
+		return false
+	}
+
+	return len(source.Content) > 0 || len(source.Attachments) > 0
+}
+
+// whatsapp chat id from contact identifier, or phone number
+func (source *QpChatwootWebhookPayload) GetChatId() (string, error) {
+	if source.Conversation == nil {
+		return "", fmt.Errorf("chatwoot conversation missing")
+	}
+
+	contact := source.Conversation.Meta.Sender
+	if len(contact.Identifier) > 0 {
+		return whatsapp.FormatEndpoint(contact.Identifier)
+	}
+
+	if len(contact.PhoneNumber) > 0 {
+		return whatsapp.FormatEndpoint(contact.PhoneNumber)
+	}
+
+	return "", fmt.Errorf("chatwoot contact without identifier or phone, conversation: %v", source.Conversation.Id)
+}
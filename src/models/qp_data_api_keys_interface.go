@@ -0,0 +1,13 @@
+package models
+
+type QpDataApiKeysInterface interface {
+
+	// all keys of an user
+	FindAll(username string) ([]*QpApiKey, error)
+
+	// nil if not found
+	FindByHash(hash string) (*QpApiKey, error)
+
+	Add(element *QpApiKey) error
+	Remove(username string, id string) (uint, error)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type QpDataApiKeysSql struct {
+	db *sqlx.DB
+}
+
+func (source QpDataApiKeysSql) FindAll(username string) ([]*QpApiKey, error) {
+	result := []*QpApiKey{}
+	query := source.db.Rebind(`SELECT * FROM api_keys WHERE username = ? ORDER BY timestamp`)
+	err := source.db.Select(&result, query, username)
+	return result, err
+}
+
+func (source QpDataApiKeysSql) FindByHash(hash string) (*QpApiKey, error) {
+	result := &QpApiKey{}
+	query := source.db.Rebind(`SELECT * FROM api_keys WHERE hash = ?`)
+	err := source.db.Get(result, query, hash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return result, err
+}
+
+func (source QpDataApiKeysSql) Add(element *QpApiKey) error {
+	query := `INSERT INTO api_keys (id, username, name, hash, prefix, scopes, server, expires, timestamp) VALUES (:id, :username, :name, :hash, :prefix, :scopes, :server, :expires, :timestamp)`
+	_, err := source.db.NamedExec(query, element)
+	return err
+}
+
+func (source QpDataApiKeysSql) Remove(username string, id string) (affected uint, err error) {
+	query := source.db.Rebind(`DELETE FROM api_keys WHERE username = ? AND id = ?`)
+	result, err := source.db.Exec(query, username, id)
+	if err != nil {
+		return
+	}
+
+	rows, err := result.RowsAffected()
+	affected = uint(rows)
+	return
+}
@@ -0,0 +1,11 @@
+package models
+
+type QpDataAuditInterface interface {
+	Add(element *QpAuditEntry) error
+
+	// newest first, paginated by filter limit and offset
+	Find(filter *QpAuditFilter) ([]*QpAuditEntry, error)
+
+	// total entries matching the filter, ignoring pagination
+	Count(filter *QpAuditFilter) (uint, error)
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type QpDataAuditSql struct {
+	db *sqlx.DB
+}
+
+func (source QpDataAuditSql) Add(element *QpAuditEntry) error {
+	query := `INSERT INTO audit_log (id, timestamp, actortype, actor, action, target, resource, digest, details) VALUES (:id, :timestamp, :actortype, :actor, :action, :target, :resource, :digest, :details)`
+	_, err := source.db.NamedExec(query, element)
+	return err
+}
+
+func (source QpDataAuditSql) filter(filter *QpAuditFilter) (where string, args []interface{}) {
+	conditions := []string{}
+
+	equals := [][2]string{
+		{"actortype", filter.ActorType},
+		{"actor", filter.Actor},
+		{"target", filter.Target},
+		{"resource", filter.Resource},
+	}
+
+	for _, item := range equals {
+		if len(item[1]) > 0 {
+			conditions = append(conditions, item[0]+` = ?`)
+			args = append(args, item[1])
+		}
+	}
+
+	if len(filter.Action) > 0 {
+		if strings.HasSuffix(filter.Action, ".") {
+			conditions = append(conditions, `action LIKE ?`)
+			args = append(args, filter.Action+"%")
+		} else {
+			conditions = append(conditions, `action = ?`)
+			args = append(args, filter.Action)
+		}
+	}
+
+	if filter.Since != nil {
+		conditions = append(conditions, `timestamp >= ?`)
+		args = append(args, filter.Since.UTC())
+	}
+
+	if filter.Until != nil {
+		conditions = append(conditions, `timestamp < ?`)
+		args = append(args, filter.Until.UTC())
+	}
+
+	if len(conditions) > 0 {
+		where = ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+
+	return
+}
+
+func (source QpDataAuditSql) Find(filter *QpAuditFilter) ([]*QpAuditEntry, error) {
+	result := []*QpAuditEntry{}
+	where, args := source.filter(filter)
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := source.db.Rebind(`SELECT * FROM audit_log` + where + ` ORDER BY timestamp DESC, id LIMIT ? OFFSET ?`)
+	err := source.db.Select(&result, query, args...)
+	return result, err
+}
+
+func (source QpDataAuditSql) Count(filter *QpAuditFilter) (result uint, err error) {
+	where, args := source.filter(filter)
+	query := source.db.Rebind(`SELECT count(*) FROM audit_log` + where)
+	err = source.db.Get(&result, query, args...)
+	return
+}
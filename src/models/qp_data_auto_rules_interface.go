@@ -0,0 +1,14 @@
+package models
+
+type QpDataAutoRulesInterface interface {
+
+	// all rules of a server, ordered by priority
+	FindAll(context string) ([]*QpAutoRule, error)
+
+	// nil if not found
+	FindById(context string, id string) (*QpAutoRule, error)
+
+	Add(element *QpAutoRule) error
+	Update(element *QpAutoRule) error
+	Remove(context string, id string) (uint, error)
+}
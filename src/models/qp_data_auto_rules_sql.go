@@ -0,0 +1,54 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type QpDataAutoRulesSql struct {
+	db *sqlx.DB
+}
+
+func (source QpDataAutoRulesSql) FindAll(context string) ([]*QpAutoRule, error) {
+	result := []*QpAutoRule{}
+	query := source.db.Rebind(`SELECT * FROM auto_rules WHERE context = ? ORDER BY priority, timestamp`)
+	err := source.db.Select(&result, query, context)
+	return result, err
+}
+
+func (source QpDataAutoRulesSql) FindById(context string, id string) (*QpAutoRule, error) {
+	result := &QpAutoRule{}
+	query := source.db.Rebind(`SELECT * FROM auto_rules WHERE context = ? AND id = ?`)
+	err := source.db.Get(result, query, context, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return result, err
+}
+
+func (source QpDataAutoRulesSql) Add(element *QpAutoRule) error {
+	query := `INSERT INTO auto_rules (id, context, name, priority, disabled, conditions, action, timestamp) VALUES (:id, :context, :name, :priority, :disabled, :conditions, :action, :timestamp)`
+	_, err := source.db.NamedExec(query, element)
+	return err
+}
+
+func (source QpDataAutoRulesSql) Update(element *QpAutoRule) error {
+	query := `UPDATE auto_rules SET name = :name, priority = :priority, disabled = :disabled, conditions = :conditions, action = :action WHERE context = :context AND id = :id`
+	_, err := source.db.NamedExec(query, element)
+	return err
+}
+
+func (source QpDataAutoRulesSql) Remove(context string, id string) (affected uint, err error) {
+	query := source.db.Rebind(`DELETE FROM auto_rules WHERE context = ? AND id = ?`)
+	result, err := source.db.Exec(query, context, id)
+	if err != nil {
+		return
+	}
+
+	rows, err := result.RowsAffected()
+	affected = uint(rows)
+	return
+}
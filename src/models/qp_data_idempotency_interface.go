@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type QpDataIdempotencyInterface interface {
+
+	// reserves the key, if already exists (and not expired) returns its response, empty if still in progress
+	// keys without response reserved before pending are abandoned (ex: crash while sending) and reclaimed
+	Reserve(context string, key string, expiration time.Time, pending time.Time) (response []byte, reserved bool, err error)
+
+	// saves the response of a reserved key
+	Complete(context string, key string, response []byte) error
+
+	// removes a reserved key, allowing retries
+	Release(context string, key string) error
+
+	// removes keys older than time
+	CleanUp(before time.Time) (uint, error)
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type QpDataIdempotencySql struct {
+	db *sqlx.DB
+}
+
+func (source QpDataIdempotencySql) Reserve(context string, key string, expiration time.Time, pending time.Time) (response []byte, reserved bool, err error) {
+
+	// removing expired or abandoned record for this key, if exists
+	query := source.db.Rebind(`DELETE FROM send_idempotency WHERE context = ? AND requestkey = ? AND (timestamp < ? OR (response IS NULL AND timestamp < ?))`)
+	_, err = source.db.Exec(query, context, key, expiration.UTC(), pending.UTC())
+	if err != nil {
+		return
+	}
+
+	query = source.db.Rebind(`INSERT INTO send_idempotency (context, requestkey, timestamp) VALUES (?, ?, ?)`)
+	_, insertErr := source.db.Exec(query, context, key, time.Now().UTC())
+	if insertErr == nil {
+		return nil, true, nil
+	}
+
+	// primary key conflict, getting the previous response
+	var previous []byte
+	query = source.db.Rebind(`SELECT response FROM send_idempotency WHERE context = ? AND requestkey = ?`)
+	err = source.db.Get(&previous, query, context, key)
+	if err == sql.ErrNoRows {
+		return nil, false, insertErr
+	}
+
+	return previous, false, err
+}
+
+func (source QpDataIdempotencySql) Complete(context string, key string, response []byte) error {
+	query := source.db.Rebind(`UPDATE send_idempotency SET response = ? WHERE context = ? AND requestkey = ?`)
+	_, err := source.db.Exec(query, response, context, key)
+	return err
+}
+
+func (source QpDataIdempotencySql) Release(context string, key string) error {
+	query := source.db.Rebind(`DELETE FROM send_idempotency WHERE context = ? AND requestkey = ?`)
+	_, err := source.db.Exec(query, context, key)
+	return err
+}
+
+func (source QpDataIdempotencySql) CleanUp(before time.Time) (affected uint, err error) {
+	query := source.db.Rebind(`DELETE FROM send_idempotency WHERE timestamp < ?`)
+	result, err := source.db.Exec(query, before.UTC())
+	if err != nil {
+		return
+	}
+
+	rows, err := result.RowsAffected()
+	affected = uint(rows)
+	return
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type QpDataMessagesInterface interface {
+	Find(context string, id string) (*QpServerMessage, error)
+	FindByPrefix(context string, prefix string) ([]*QpServerMessage, error)
+	FindByTime(context string, timestamp time.Time) ([]*QpServerMessage, error)
+	Search(context string, filter *QpMessageSearchFilter) ([]*QpServerMessage, error)
+	SearchCount(context string, filter *QpMessageSearchFilter) (uint, error)
+	CountPrevious(context string, chatid string, id string, timestamp time.Time) (uint, error)
+	Add(element *QpServerMessage) error
+	UpdateStatus(context string, id string, status string) error
+	CleanUp(before time.Time) (int64, error)
+	CleanUpLength(context string, max uint64) (int64, error)
+	Contexts() ([]string, error)
+	Clear(context string) error
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type QpDataSendQueueInterface interface {
+	Enqueue(element *QpSendQueueItem) error
+
+	// next queued item due before time, nil if none
+	FindNext(context string, before time.Time) (*QpSendQueueItem, error)
+
+	// earliest queued item, nil if none
+	FindFirst(context string) (*QpSendQueueItem, error)
+
+	// all items, queued and failed
+	FindAll(context string) ([]*QpSendQueueItem, error)
+
+	Update(element *QpSendQueueItem) error
+
+	// removes one item or all if id is empty
+	Remove(context string, id string) (uint, error)
+
+	Clear(context string) error
+}
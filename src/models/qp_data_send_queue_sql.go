@@ -0,0 +1,75 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type QpDataSendQueueSql struct {
+	db *sqlx.DB
+}
+
+func (source QpDataSendQueueSql) Enqueue(element *QpSendQueueItem) error {
+	query := `INSERT INTO send_queue (id, context, chatid, trackid, message, content, ptt, status, lasterror, attempts, sendat, timestamp) VALUES (:id, :context, :chatid, :trackid, :message, :content, :ptt, :status, :lasterror, :attempts, :sendat, :timestamp)`
+	_, err := source.db.NamedExec(query, element)
+	return err
+}
+
+func (source QpDataSendQueueSql) findOne(query string, args ...interface{}) (*QpSendQueueItem, error) {
+	result := &QpSendQueueItem{}
+	err := source.db.Get(result, source.db.Rebind(query), args...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return result, err
+}
+
+func (source QpDataSendQueueSql) FindNext(context string, before time.Time) (*QpSendQueueItem, error) {
+	query := `SELECT * FROM send_queue WHERE context = ? AND status = ? AND sendat <= ? ORDER BY sendat, timestamp LIMIT 1`
+	return source.findOne(query, context, SendQueueStatusQueued, before.UTC())
+}
+
+func (source QpDataSendQueueSql) FindFirst(context string) (*QpSendQueueItem, error) {
+	query := `SELECT * FROM send_queue WHERE context = ? AND status = ? ORDER BY sendat, timestamp LIMIT 1`
+	return source.findOne(query, context, SendQueueStatusQueued)
+}
+
+func (source QpDataSendQueueSql) FindAll(context string) ([]*QpSendQueueItem, error) {
+	result := []*QpSendQueueItem{}
+	query := source.db.Rebind(`SELECT * FROM send_queue WHERE context = ? ORDER BY sendat, timestamp`)
+	err := source.db.Select(&result, query, context)
+	return result, err
+}
+
+func (source QpDataSendQueueSql) Update(element *QpSendQueueItem) error {
+	query := source.db.Rebind(`UPDATE send_queue SET status = ?, lasterror = ?, attempts = ?, sendat = ? WHERE context = ? AND id = ?`)
+	_, err := source.db.Exec(query, element.Status, element.LastError, element.Attempts, element.SendAt.UTC(), element.Context, element.Id)
+	return err
+}
+
+func (source QpDataSendQueueSql) Remove(context string, id string) (affected uint, err error) {
+	query := `DELETE FROM send_queue WHERE context = ?`
+	args := []interface{}{context}
+	if len(id) > 0 {
+		query += ` AND id = ?`
+		args = append(args, id)
+	}
+
+	result, err := source.db.Exec(source.db.Rebind(query), args...)
+	if err != nil {
+		return
+	}
+
+	rows, err := result.RowsAffected()
+	affected = uint(rows)
+	return
+}
+
+func (source QpDataSendQueueSql) Clear(context string) error {
+	_, err := source.Remove(context, "")
+	return err
+}
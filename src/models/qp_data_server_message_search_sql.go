@@ -0,0 +1,236 @@
+package models
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	migrate "github.com/joncalhoun/migrate"
+	log "github.com/sirupsen/logrus"
+)
+
+// Full text engines for stored messages search, by database driver
+const (
+	MessageSearchFTS4     = "fts4"
+	MessageSearchTsVector = "tsvector"
+	MessageSearchLike     = "like"
+)
+
+// Migration creating the full text index, see GetMessagesSearchMigration
+const MessageSearchMigrationId = "202610182310"
+
+var (
+	messageSearchSync   sync.Once
+	messageSearchEngine string
+)
+
+/*
+<summary>
+
+	Full text index, created by migration, for each database driver
+	* sqlite: fts4 virtual table, with docid from a key table on (context, id), synced by triggers
+	  messages has no integer primary key, its implicit rowid may change on vacuum
+	  fts5 is not used, go-sqlite3 only includes it with "sqlite_fts5" build tag
+	* postgres: gin index over tsvector of search column
+	* others: none, searching with like
+
+</summary>
+*/
+func GetMessagesSearchMigration() migrate.SqlxMigration {
+	return migrate.SqlxMigration{
+		ID: MessageSearchMigrationId,
+		Migrate: func(tx *sqlx.Tx) error {
+			var statements []string
+			switch tx.DriverName() {
+			case "sqlite3":
+				statements = []string{
+					`CREATE TABLE IF NOT EXISTS messages_search_keys (docid INTEGER PRIMARY KEY AUTOINCREMENT, context CHAR (100) NOT NULL, id VARCHAR (255) NOT NULL, CONSTRAINT messages_search_keys_unique UNIQUE (context, id))`,
+					`CREATE VIRTUAL TABLE IF NOT EXISTS messages_search USING fts4(search, tokenize=unicode61 "remove_diacritics=1")`,
+					`CREATE TRIGGER IF NOT EXISTS messages_search_insert AFTER INSERT ON messages BEGIN
+						INSERT OR IGNORE INTO messages_search_keys (context, id) VALUES (new.context, new.id);
+						INSERT OR REPLACE INTO messages_search (docid, search) SELECT docid, new.search FROM messages_search_keys WHERE context = new.context AND id = new.id;
+					END`,
+					`CREATE TRIGGER IF NOT EXISTS messages_search_update AFTER UPDATE OF search ON messages BEGIN
+						UPDATE messages_search SET search = new.search WHERE docid = (SELECT docid FROM messages_search_keys WHERE context = old.context AND id = old.id);
+					END`,
+					`CREATE TRIGGER IF NOT EXISTS messages_search_delete AFTER DELETE ON messages BEGIN
+						DELETE FROM messages_search WHERE docid = (SELECT docid FROM messages_search_keys WHERE context = old.context AND id = old.id);
+						DELETE FROM messages_search_keys WHERE context = old.context AND id = old.id;
+					END`,
+
+					// indexing messages stored before
+					`INSERT OR IGNORE INTO messages_search_keys (context, id) SELECT context, id FROM messages`,
+					`INSERT OR REPLACE INTO messages_search (docid, search) SELECT messages_search_keys.docid, messages.search FROM messages JOIN messages_search_keys ON messages_search_keys.context = messages.context AND messages_search_keys.id = messages.id`,
+				}
+			case "postgres":
+				statements = []string{
+					`CREATE INDEX IF NOT EXISTS messages_search_tsvector ON messages USING GIN (to_tsvector('simple', search))`,
+				}
+			}
+
+			for _, statement := range statements {
+				_, err := tx.Exec(statement)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Fills search column of messages stored before it, from their payloads, runs after migration
+func MigrationHandler_202610182310(id string) {
+	log.Infof("running migration handler for: %s", id)
+
+	store := QpDataServerMessageSql{GetDB()}
+	count, err := store.Reindex()
+	if err != nil {
+		log.Errorf("error at reindexing messages search: %s", err.Error())
+		return
+	}
+
+	log.Infof("messages search reindexed: %v", count)
+}
+
+// Engine in use, detected once from the database driver and migrated index
+func (source QpDataServerMessageSql) GetSearchEngine() string {
+	messageSearchSync.Do(func() {
+		messageSearchEngine = source.getSearchEngine()
+		log.Debugf("messages search engine: %s", messageSearchEngine)
+	})
+	return messageSearchEngine
+}
+
+func (source QpDataServerMessageSql) getSearchEngine() string {
+	switch source.db.DriverName() {
+	case "sqlite3":
+		var existing []string
+		err := source.db.Select(&existing, `SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'messages_search'`)
+		if err != nil || len(existing) == 0 {
+			return MessageSearchLike
+		}
+		return MessageSearchFTS4
+	case "postgres":
+		return MessageSearchTsVector
+	default:
+		return MessageSearchLike
+	}
+}
+
+// Recalculates search content of all stored messages, paging by (context, id)
+func (source QpDataServerMessageSql) Reindex() (count int64, err error) {
+	var context, id string
+	for {
+		elements := []*QpServerMessage{}
+		query := source.db.Rebind(`SELECT * FROM messages WHERE context > ? OR (context = ? AND id > ?) ORDER BY context, id LIMIT 500`)
+		err = source.db.Select(&elements, query, context, context, id)
+		if err != nil || len(elements) == 0 {
+			return
+		}
+
+		for _, element := range elements {
+			context, id = element.Context, element.Id
+
+			msg, err := element.ToWhatsappMessage()
+			if err != nil {
+				log.Warnf("error at decoding stored message: %s, cause: %s", element.Id, err.Error())
+				continue
+			}
+
+			search := GetMessageSearchContent(msg)
+			if search == element.Search {
+				continue
+			}
+
+			query := source.db.Rebind(`UPDATE messages SET search = ? WHERE context = ? AND id = ?`)
+			_, err = source.db.Exec(query, search, element.Context, element.Id)
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+}
+
+// Full text match expression, all terms required as word prefixes, quotes are removed (fts4 has no escaping)
+func GetFullTextMatch(terms []string) string {
+	quoted := make([]string, len(terms))
+	for index, term := range terms {
+		quoted[index] = `"` + strings.ReplaceAll(term, `"`, "") + `*"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Postgres tsquery expression, all terms required as word prefixes
+func GetTsQueryMatch(terms []string) string {
+	prefixes := make([]string, len(terms))
+	for index, term := range terms {
+		prefixes[index] = `'` + strings.ReplaceAll(term, `'`, "") + `':*`
+	}
+	return strings.Join(prefixes, " & ")
+}
+
+func (source QpDataServerMessageSql) searchFilter(context string, filter *QpMessageSearchFilter) (where string, args []interface{}) {
+	conditions := []string{`context = ?`}
+	args = append(args, context)
+
+	terms := filter.GetTerms()
+	if len(terms) > 0 {
+		switch source.GetSearchEngine() {
+		case MessageSearchFTS4:
+			conditions = append(conditions, `id IN (SELECT messages_search_keys.id FROM messages_search JOIN messages_search_keys ON messages_search_keys.docid = messages_search.docid WHERE messages_search MATCH ? AND messages_search_keys.context = ?)`)
+			args = append(args, GetFullTextMatch(terms), context)
+		case MessageSearchTsVector:
+			conditions = append(conditions, `to_tsvector('simple', search) @@ to_tsquery('simple', ?)`)
+			args = append(args, GetTsQueryMatch(terms))
+		default:
+			// search column has folded words separated by spaces, matching prefix of first or any other word
+			for _, term := range terms {
+				conditions = append(conditions, `(search LIKE ? OR search LIKE ?)`)
+				args = append(args, term+"%", "% "+term+"%")
+			}
+		}
+	}
+
+	if len(filter.ChatId) > 0 {
+		conditions = append(conditions, `chatid = ?`)
+		args = append(args, filter.ChatId)
+	}
+
+	if len(filter.Type) > 0 {
+		conditions = append(conditions, `type = ?`)
+		args = append(args, strings.ToLower(filter.Type))
+	}
+
+	if filter.From != nil {
+		conditions = append(conditions, `timestamp >= ?`)
+		args = append(args, filter.From.UTC())
+	}
+
+	if filter.To != nil {
+		conditions = append(conditions, `timestamp <= ?`)
+		args = append(args, filter.To.UTC())
+	}
+
+	where = ` WHERE ` + strings.Join(conditions, ` AND `)
+	return
+}
+
+// Stored messages matching search, newest first
+func (source QpDataServerMessageSql) Search(context string, filter *QpMessageSearchFilter) ([]*QpServerMessage, error) {
+	result := []*QpServerMessage{}
+	where, args := source.searchFilter(context, filter)
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := source.db.Rebind(`SELECT * FROM messages` + where + ` ORDER BY timestamp DESC, id LIMIT ? OFFSET ?`)
+	err := source.db.Select(&result, query, args...)
+	return result, err
+}
+
+func (source QpDataServerMessageSql) SearchCount(context string, filter *QpMessageSearchFilter) (result uint, err error) {
+	where, args := source.searchFilter(context, filter)
+	query := source.db.Rebind(`SELECT count(*) FROM messages` + where)
+	err = source.db.Get(&result, query, args...)
+	return
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Message store using the same database of servers, rebinding placeholders for each driver
+type QpDataServerMessageSql struct {
+	db *sqlx.DB
+}
+
+func (source QpDataServerMessageSql) Find(context string, id string) (*QpServerMessage, error) {
+	result := &QpServerMessage{}
+	query := source.db.Rebind("SELECT * FROM messages WHERE context = ? AND id = ?")
+	err := source.db.Get(result, query, context, strings.ToUpper(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (source QpDataServerMessageSql) FindByPrefix(context string, prefix string) ([]*QpServerMessage, error) {
+	result := []*QpServerMessage{}
+	query := source.db.Rebind("SELECT * FROM messages WHERE context = ? AND id LIKE ?")
+	err := source.db.Select(&result, query, context, strings.ToUpper(prefix)+"%")
+	return result, err
+}
+
+func (source QpDataServerMessageSql) FindByTime(context string, timestamp time.Time) ([]*QpServerMessage, error) {
+	result := []*QpServerMessage{}
+	query := source.db.Rebind("SELECT * FROM messages WHERE context = ? AND timestamp > ? ORDER BY timestamp")
+	err := source.db.Select(&result, query, context, timestamp.UTC())
+	return result, err
+}
+
+// messages of a chat until a timestamp, except a specific id
+func (source QpDataServerMessageSql) CountPrevious(context string, chatid string, id string, timestamp time.Time) (uint, error) {
+	var count uint
+	query := source.db.Rebind("SELECT COUNT(*) FROM messages WHERE context = ? AND chatid = ? AND id <> ? AND timestamp <= ?")
+	err := source.db.Get(&count, query, context, chatid, strings.ToUpper(id), timestamp.UTC())
+	return count, err
+}
+
+// insert or update (same context and id), avoiding driver specific upsert syntax
+func (source QpDataServerMessageSql) Add(element *QpServerMessage) error {
+	query := source.db.Rebind(`UPDATE messages SET chatid = ?, participant = ?, type = ?, status = ?, text = ?, fromme = ?, payload = ?, content = ?, info = ?, search = ?, timestamp = ? WHERE context = ? AND id = ?`)
+	result, err := source.db.Exec(query, element.ChatId, element.Participant, element.Type, element.Status, element.Text, element.FromMe, element.Payload, element.Content, element.Info, element.Search, element.Timestamp, element.Context, element.Id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+
+	query = source.db.Rebind(`INSERT INTO messages (context, id, chatid, participant, type, status, text, fromme, payload, content, info, search, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	_, err = source.db.Exec(query, element.Context, element.Id, element.ChatId, element.Participant, element.Type, element.Status, element.Text, element.FromMe, element.Payload, element.Content, element.Info, element.Search, element.Timestamp)
+	return err
+}
+
+func (source QpDataServerMessageSql) UpdateStatus(context string, id string, status string) error {
+	query := source.db.Rebind(`UPDATE messages SET status = ? WHERE context = ? AND id = ?`)
+	_, err := source.db.Exec(query, status, context, strings.ToUpper(id))
+	return err
+}
+
+// removes messages older than a specific time, for all contexts
+func (source QpDataServerMessageSql) CleanUp(before time.Time) (int64, error) {
+	query := source.db.Rebind(`DELETE FROM messages WHERE timestamp < ?`)
+	result, err := source.db.Exec(query, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// removes old messages, by timestamp, until a maximum length for a specific context
+func (source QpDataServerMessageSql) CleanUpLength(context string, max uint64) (int64, error) {
+	if max == 0 {
+		return 0, nil
+	}
+
+	var limits []time.Time
+	query := source.db.Rebind(`SELECT timestamp FROM messages WHERE context = ? ORDER BY timestamp DESC LIMIT 1 OFFSET ?`)
+	err := source.db.Select(&limits, query, context, max-1)
+	if err != nil || len(limits) == 0 {
+		return 0, err
+	}
+
+	query = source.db.Rebind(`DELETE FROM messages WHERE context = ? AND timestamp < ?`)
+	result, err := source.db.Exec(query, context, limits[0])
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// distinct contexts with stored messages
+func (source QpDataServerMessageSql) Contexts() ([]string, error) {
+	result := []string{}
+	err := source.db.Select(&result, "SELECT DISTINCT context FROM messages")
+	return result, err
+}
+
+func (source QpDataServerMessageSql) Clear(context string) error {
+	query := source.db.Rebind(`DELETE FROM messages WHERE context = ?`)
+	_, err := source.db.Exec(query, context)
+	return err
+}
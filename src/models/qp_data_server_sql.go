@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
@@ -12,13 +13,37 @@ type QpDataServerSql struct {
 	db *sqlx.DB
 }
 
+// wid column is unique and not null, servers awaiting pairing are stored as "{token}@pending"
+const ServerPendingWidSuffix = "@pending"
+
+func toStoredServer(element *QpServer) *QpServer {
+	if len(element.Wid) > 0 {
+		return element
+	}
+
+	stored := *element
+	stored.Wid = element.Token + ServerPendingWidSuffix
+	return &stored
+}
+
+func fromStoredServer(element *QpServer) *QpServer {
+	if element != nil && strings.HasSuffix(element.Wid, ServerPendingWidSuffix) {
+		element.Wid = ""
+	}
+	return element
+}
+
 func (source QpDataServerSql) FindForUser(token string, user string) (response *QpServer, err error) {
 	err = source.db.Get(&response, "SELECT * FROM servers WHERE token = ? AND user = ?", token, user)
+	response = fromStoredServer(response)
 	return
 }
 
 func (source QpDataServerSql) FindAll() (response []*QpServer) {
 	_ = source.db.Select(&response, "SELECT * FROM servers")
+	for _, element := range response {
+		fromStoredServer(element)
+	}
 	return
 }
 
@@ -38,23 +63,44 @@ func (source QpDataServerSql) Exists(token string) (bool, error) {
 
 func (source QpDataServerSql) FindByToken(token string) (response *QpServer, err error) {
 	err = source.db.Get(&response, "SELECT * FROM servers WHERE token = ?", token)
+	response = fromStoredServer(response)
 	return
 }
 
 func (source QpDataServerSql) Add(element *QpServer) error {
-	query := `INSERT INTO servers (token, wid, verified, devel, groups, broadcasts, readreceipts, calls, user) VALUES (:token, :wid, :verified, :devel, :groups, :broadcasts, :readreceipts, :calls, :user)`
-	_, err := source.db.NamedExec(query, element)
+	query := `INSERT INTO servers (token, wid, verified, devel, groups, broadcasts, readreceipts, calls, callrules, sendqueue, chatwoot, user) VALUES (:token, :wid, :verified, :devel, :groups, :broadcasts, :readreceipts, :calls, :callrules, :sendqueue, :chatwoot, :user)`
+	_, err := source.db.NamedExec(query, toStoredServer(element))
 	return err
 }
 
 func (source QpDataServerSql) Update(element *QpServer) error {
-	query := `UPDATE servers SET wid = :wid, verified = :verified, devel = :devel, groups = :groups, broadcasts = :broadcasts, readreceipts = :readreceipts, calls = :calls, user = :user WHERE token = :token`
-	_, err := source.db.NamedExec(query, element)
+	query := `UPDATE servers SET wid = :wid, verified = :verified, devel = :devel, groups = :groups, broadcasts = :broadcasts, readreceipts = :readreceipts, calls = :calls, callrules = :callrules, sendqueue = :sendqueue, chatwoot = :chatwoot, user = :user WHERE token = :token`
+	_, err := source.db.NamedExec(query, toStoredServer(element))
 	return err
 }
 
-func (source QpDataServerSql) Delete(token string) error {
-	query := `DELETE FROM servers WHERE token = ?`
-	_, err := source.db.Exec(query, token)
-	return err
+// removes the server with its user assignments, bound api keys and auto rules,
+// so a new server created with the same token does not inherit them
+func (source QpDataServerSql) Delete(token string) (err error) {
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return
+	}
+
+	queries := []string{
+		`DELETE FROM user_servers WHERE token = ?`,
+		`DELETE FROM api_keys WHERE server = ?`,
+		`DELETE FROM auto_rules WHERE context = ?`,
+		`DELETE FROM servers WHERE token = ?`,
+	}
+
+	for _, query := range queries {
+		_, err = tx.Exec(tx.Rebind(query), token)
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}
+
+	return tx.Commit()
 }
@@ -22,6 +22,11 @@ type QpDataUserSql struct {
 	Find(string) (*QpUser, error)
 	Check(string, password string) (*QpUser, error)
 	Create(string, password string) (*QpUser, error)
+	FindAll() ([]*QpUser, error)
+	UpdateRole(string, role string) error
+	GetServers(string) ([]string, error)
+	AddServer(string, token string) error
+	RemoveServer(string, token string) (uint, error)
 */
 
 func (source QpDataUserSql) Count() (result int, err error) {
@@ -86,6 +91,7 @@ func (source QpDataUserSql) Create(username string, password string) (result *Qp
 	user := &QpUser{
 		Username: username,
 		Password: string(hashed),
+		Role:     UserRoleOperator,
 	}
 
 	query := `INSERT INTO users (username, password) VALUES (:username, :password)`
@@ -97,3 +103,48 @@ func (source QpDataUserSql) Create(username string, password string) (result *Qp
 	result = user
 	return
 }
+
+func (source QpDataUserSql) FindAll() (result []*QpUser, err error) {
+	result = []*QpUser{}
+	err = source.db.Select(&result, "SELECT * FROM users ORDER BY username")
+	return
+}
+
+func (source QpDataUserSql) UpdateRole(username string, role string) error {
+	query := source.db.Rebind(`UPDATE users SET role = ? WHERE username = ?`)
+	result, err := source.db.Exec(query, role, username)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err == nil && rows == 0 {
+		err = fmt.Errorf("user (%s) not found for update role", username)
+	}
+	return err
+}
+
+func (source QpDataUserSql) GetServers(username string) (result []string, err error) {
+	result = []string{}
+	query := source.db.Rebind(`SELECT token FROM user_servers WHERE username = ? ORDER BY token`)
+	err = source.db.Select(&result, query, username)
+	return
+}
+
+func (source QpDataUserSql) AddServer(username string, token string) error {
+	query := source.db.Rebind(`INSERT INTO user_servers (username, token) VALUES (?, ?)`)
+	_, err := source.db.Exec(query, username, token)
+	return err
+}
+
+func (source QpDataUserSql) RemoveServer(username string, token string) (affected uint, err error) {
+	query := source.db.Rebind(`DELETE FROM user_servers WHERE username = ? AND token = ?`)
+	result, err := source.db.Exec(query, username, token)
+	if err != nil {
+		return
+	}
+
+	rows, err := result.RowsAffected()
+	affected = uint(rows)
+	return
+}
@@ -6,4 +6,12 @@ type QpDataUsersInterface interface {
 	Exists(string) (bool, error)
 	Check(string, string) (*QpUser, error)
 	Create(string, string) (*QpUser, error)
+
+	FindAll() ([]*QpUser, error)
+	UpdateRole(username string, role string) error
+
+	// servers assigned to an user, besides owned ones
+	GetServers(username string) ([]string, error)
+	AddServer(username string, token string) error
+	RemoveServer(username string, token string) (uint, error)
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type QpDataWebhookDeliveriesInterface interface {
+
+	// pending queue
+	Enqueue(element *QpWebhookDelivery) error
+	FindPending(context string, before time.Time, limit uint) ([]*QpWebhookDelivery, error)
+	Update(element *QpWebhookDelivery) error
+	Remove(id string) error
+
+	// dead letters
+	Kill(element *QpWebhookDelivery) error
+	FindDead(context string, url string) ([]*QpWebhookDelivery, error)
+	Revive(context string, url string, id string) (uint, error)
+	Purge(context string, url string, id string) (uint, error)
+
+	// both, pending and dead letters
+	Clear(context string) error
+}
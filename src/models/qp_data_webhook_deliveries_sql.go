@@ -0,0 +1,164 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type QpDataWebhookDeliveriesSql struct {
+	db *sqlx.DB
+}
+
+const webhookDeliveryColumns = `id, context, url, messageid, payload, attempts, nextattempt, lasterror, timestamp`
+const webhookDeliveryValues = `:id, :context, :url, :messageid, :payload, :attempts, :nextattempt, :lasterror, :timestamp`
+
+//#region PENDING QUEUE
+
+func (source QpDataWebhookDeliveriesSql) Enqueue(element *QpWebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (` + webhookDeliveryColumns + `) VALUES (` + webhookDeliveryValues + `)`
+	_, err := source.db.NamedExec(query, element)
+	return err
+}
+
+func (source QpDataWebhookDeliveriesSql) FindPending(context string, before time.Time, limit uint) ([]*QpWebhookDelivery, error) {
+	result := []*QpWebhookDelivery{}
+	query := source.db.Rebind(`SELECT * FROM webhook_deliveries WHERE context = ? AND nextattempt <= ? ORDER BY nextattempt LIMIT ?`)
+	err := source.db.Select(&result, query, context, before.UTC(), limit)
+	return result, err
+}
+
+func (source QpDataWebhookDeliveriesSql) Update(element *QpWebhookDelivery) error {
+	query := source.db.Rebind(`UPDATE webhook_deliveries SET attempts = ?, nextattempt = ?, lasterror = ? WHERE id = ?`)
+	_, err := source.db.Exec(query, element.Attempts, element.NextAttempt.UTC(), element.LastError, element.Id)
+	return err
+}
+
+func (source QpDataWebhookDeliveriesSql) Remove(id string) error {
+	query := source.db.Rebind(`DELETE FROM webhook_deliveries WHERE id = ?`)
+	_, err := source.db.Exec(query, id)
+	return err
+}
+
+//#endregion
+//#region DEAD LETTERS
+
+// moves a pending delivery to dead letters
+func (source QpDataWebhookDeliveriesSql) Kill(element *QpWebhookDelivery) (err error) {
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return
+	}
+
+	query := `INSERT INTO webhook_deadletters (` + webhookDeliveryColumns + `) VALUES (` + webhookDeliveryValues + `)`
+	_, err = tx.NamedExec(query, element)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	_, err = tx.Exec(tx.Rebind(`DELETE FROM webhook_deliveries WHERE id = ?`), element.Id)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	return tx.Commit()
+}
+
+// filter for dead letters, empty url or id means all
+func (source QpDataWebhookDeliveriesSql) deadFilter(context string, url string, id string) (where string, args []interface{}) {
+	where = ` WHERE context = ?`
+	args = append(args, context)
+
+	if len(url) > 0 {
+		where += ` AND url = ?`
+		args = append(args, url)
+	}
+
+	if len(id) > 0 {
+		where += ` AND id = ?`
+		args = append(args, id)
+	}
+
+	return
+}
+
+func (source QpDataWebhookDeliveriesSql) FindDead(context string, url string) ([]*QpWebhookDelivery, error) {
+	result := []*QpWebhookDelivery{}
+	where, args := source.deadFilter(context, url, "")
+	query := source.db.Rebind(`SELECT * FROM webhook_deadletters` + where + ` ORDER BY timestamp`)
+	err := source.db.Select(&result, query, args...)
+	return result, err
+}
+
+// moves dead letters back to pending queue, resetting attempts
+func (source QpDataWebhookDeliveriesSql) Revive(context string, url string, id string) (affected uint, err error) {
+	where, args := source.deadFilter(context, url, id)
+
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return
+	}
+
+	elements := []*QpWebhookDelivery{}
+	err = tx.Select(&elements, tx.Rebind(`SELECT * FROM webhook_deadletters`+where), args...)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	now := time.Now().UTC()
+	query := `INSERT INTO webhook_deliveries (` + webhookDeliveryColumns + `) VALUES (` + webhookDeliveryValues + `)`
+	for _, element := range elements {
+		element.Attempts = 0
+		element.NextAttempt = now
+		_, err = tx.NamedExec(query, element)
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	_, err = tx.Exec(tx.Rebind(`DELETE FROM webhook_deadletters`+where), args...)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	affected = uint(len(elements))
+	return
+}
+
+func (source QpDataWebhookDeliveriesSql) Purge(context string, url string, id string) (affected uint, err error) {
+	where, args := source.deadFilter(context, url, id)
+	result, err := source.db.Exec(source.db.Rebind(`DELETE FROM webhook_deadletters`+where), args...)
+	if err != nil {
+		return
+	}
+
+	rows, err := result.RowsAffected()
+	affected = uint(rows)
+	return
+}
+
+//#endregion
+
+func (source QpDataWebhookDeliveriesSql) Clear(context string) error {
+	query := source.db.Rebind(`DELETE FROM webhook_deliveries WHERE context = ?`)
+	_, err := source.db.Exec(query, context)
+	if err != nil {
+		return err
+	}
+
+	query = source.db.Rebind(`DELETE FROM webhook_deadletters WHERE context = ?`)
+	_, err = source.db.Exec(query, context)
+	return err
+}
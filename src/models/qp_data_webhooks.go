@@ -36,6 +36,10 @@ func (source *QpDataWebhooks) WebhookFill(info *QpServer, db QpDataWebhooksInter
 
 		element.Wid = info.Wid
 		webhooks = append(webhooks, element.QpWebhook)
+
+		if IsBrokerUrl(element.Url) {
+			RetainBrokerSink(element.Url, source.context)
+		}
 	}
 
 	logentry.Debugf("%v webhook(s) found", len(webhooks))
@@ -52,6 +56,11 @@ func (source *QpDataWebhooks) WebhookAddOrUpdate(webhook *QpWebhook) (affected u
 		return
 	}
 
+	err = webhook.Filters.Validate()
+	if err != nil {
+		return
+	}
+
 	botWHook, err := source.db.Find(source.context, webhook.Url)
 	if err != nil {
 		return
@@ -64,6 +73,14 @@ func (source *QpDataWebhooks) WebhookAddOrUpdate(webhook *QpWebhook) (affected u
 		botWHook.ReadReceipts = webhook.ReadReceipts
 		botWHook.Broadcasts = webhook.Broadcasts
 		botWHook.Extra = webhook.Extra
+		botWHook.Filters = webhook.Filters
+		botWHook.Presences = webhook.Presences
+
+		// keeping current secret if not informed, rotate to change it
+		if len(webhook.Secret) == 0 {
+			webhook.Secret = botWHook.Secret
+		}
+		botWHook.Secret = webhook.Secret
 
 		err = source.db.Update(botWHook)
 		if err != nil {
@@ -78,6 +95,10 @@ func (source *QpDataWebhooks) WebhookAddOrUpdate(webhook *QpWebhook) (affected u
 		if err != nil {
 			return
 		}
+
+		if IsBrokerUrl(webhook.Url) {
+			RetainBrokerSink(webhook.Url, source.context)
+		}
 	}
 
 	exists := false
@@ -105,6 +126,9 @@ func (source *QpDataWebhooks) WebhookRemove(url string) (affected uint, err erro
 			err = source.db.Remove(source.context, element.Url)
 			if err == nil {
 				affected++
+				if IsBrokerUrl(element.Url) {
+					ReleaseBrokerSink(element.Url, source.context)
+				}
 			} else {
 				source.Webhooks[i] = element
 				i++
@@ -124,7 +148,17 @@ func (source *QpDataWebhooks) WebhookRemove(url string) (affected uint, err erro
 }
 
 func (source *QpDataWebhooks) WebhookClear() (err error) {
-	return source.db.Clear(source.context)
+	err = source.db.Clear(source.context)
+	if err != nil {
+		return
+	}
+
+	for _, element := range source.Webhooks {
+		if IsBrokerUrl(element.Url) {
+			ReleaseBrokerSink(element.Url, source.context)
+		}
+	}
+	return
 }
 
 /*
@@ -22,11 +22,18 @@ import (
 )
 
 type QpDatabase struct {
-	Parameters library.DatabaseParameters `json:"parameters,omitempty"`
-	Connection *sqlx.DB
-	Users      QpDataUsersInterface
-	Servers    QpDataServersInterface
-	Webhooks   QpDataWebhooksInterface
+	Parameters  library.DatabaseParameters `json:"parameters,omitempty"`
+	Connection  *sqlx.DB
+	Users       QpDataUsersInterface
+	Servers     QpDataServersInterface
+	Webhooks    QpDataWebhooksInterface
+	Messages    QpDataMessagesInterface
+	Deliveries  QpDataWebhookDeliveriesInterface
+	SendQueue   QpDataSendQueueInterface
+	Idempotency QpDataIdempotencyInterface
+	AutoRules   QpDataAutoRulesInterface
+	ApiKeys     QpDataApiKeysInterface
+	Audit       QpDataAuditInterface
 }
 
 var (
@@ -70,13 +77,27 @@ func GetDatabase() *QpDatabase {
 	var iusers = QpDataUserSql{db}
 	var iwebhooks = QpDataServerWebhookSql{db}
 	var iservers = QpDataServerSql{db}
+	var imessages = QpDataServerMessageSql{db}
+	var ideliveries = QpDataWebhookDeliveriesSql{db}
+	var isendqueue = QpDataSendQueueSql{db}
+	var iidempotency = QpDataIdempotencySql{db}
+	var iautorules = QpDataAutoRulesSql{db}
+	var iapikeys = QpDataApiKeysSql{db}
+	var iaudit = QpDataAuditSql{db}
 
 	return &QpDatabase{
 		dbParameters,
 		db,
 		iusers,
 		iservers,
-		iwebhooks}
+		iwebhooks,
+		imessages,
+		ideliveries,
+		isendqueue,
+		iidempotency,
+		iautorules,
+		iapikeys,
+		iaudit}
 }
 
 // MigrateToLatest updates the database to the latest schema
@@ -166,6 +187,7 @@ func Migrations(fullPath string) (migrations []migrate.SqlxMigration) {
 	}
 
 	migrations = append(migrations, GetBase())
+	migrations = append(migrations, GetMessagesSearchMigration())
 
 	for _, migration := range confMap {
 		migrations = append(migrations, migration.ToSqlxMigration())
@@ -266,7 +288,8 @@ func (source *QpMigrator) Migrate(sqlDB *sql.DB, dialect string) error {
 var Running []string
 
 var MigrationHandlers = map[string]func(string){
-	"202303011900": MigrationHandler_202303011900,
+	"202303011900":           MigrationHandler_202303011900,
+	MessageSearchMigrationId: MigrationHandler_202610182310,
 }
 
 func MigrationHandler_202303011900(id string) {
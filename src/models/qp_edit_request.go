@@ -0,0 +1,7 @@
+package models
+
+// Request for editing the text of a sent message
+type QpEditRequest struct {
+	MessageId string `json:"messageid,omitempty"`
+	Text      string `json:"text"`
+}
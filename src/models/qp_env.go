@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	library "github.com/nocodeleaks/quepasa/library"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
@@ -34,14 +35,36 @@ const (
 	ENV_SYNOPSISLENGTH           = "SYNOPSISLENGTH"
 	ENV_CACHELENGTH              = "CACHELENGTH"
 	ENV_CACHEDAYS                = "CACHEDAYS"
+	ENV_MESSAGESTORE             = "MESSAGESTORE"       // enable durable message store on database
+	ENV_MESSAGESTOREDAYS         = "MESSAGESTOREDAYS"   // retention days for stored messages
+	ENV_MESSAGESTORELENGTH       = "MESSAGESTORELENGTH" // maximum stored messages per server
 	ENV_CONVERT_WAVE_TO_OGG      = "CONVERT_WAVE_TO_OGG"
 	ENV_COMPATIBLE_MIME_AS_AUDIO = "COMPATIBLE_MIME_AS_AUDIO"
 
+	ENV_WEBHOOK_TIMEOUT    = "WEBHOOK_TIMEOUT"    // seconds to wait for webhook response, default 10
+	ENV_WEBHOOK_RETRIES    = "WEBHOOK_RETRIES"    // retries before moving to dead letters, default 5
+	ENV_WEBHOOK_BACKOFF    = "WEBHOOK_BACKOFF"    // initial seconds between retries, doubled each attempt, default 5
+	ENV_WEBHOOK_BACKOFFMAX = "WEBHOOK_BACKOFFMAX" // maximum seconds between retries, default 3600
+
+	ENV_SENDQUEUE          = "SENDQUEUE"          // enqueue all api sends, default false
+	ENV_SENDQUEUE_RATE     = "SENDQUEUE_RATE"     // maximum messages per minute per server, default 20
+	ENV_SENDQUEUE_DELAYMIN = "SENDQUEUE_DELAYMIN" // minimum random seconds between sends, default 1
+	ENV_SENDQUEUE_DELAYMAX = "SENDQUEUE_DELAYMAX" // maximum random seconds between sends, default 5
+	ENV_SENDQUEUE_TYPING   = "SENDQUEUE_TYPING"   // seconds of typing presence before each send, default 0 (disabled)
+	ENV_SENDQUEUE_RETRIES  = "SENDQUEUE_RETRIES"  // retries of a failed queued send before marking as failed, default 3
+	ENV_SENDQUEUE_BACKOFF  = "SENDQUEUE_BACKOFF"  // initial seconds between queued send retries, doubled each attempt, default 30
+
+	ENV_IDEMPOTENCYHOURS = "IDEMPOTENCYHOURS" // hours to keep send idempotency keys, default 24, 0 disables
+
+	ENV_AUTORULES_COOLDOWN = "AUTORULES_COOLDOWN" // seconds between auto rule replies or forwards on the same chat, default 60, 0 disables
+
 	ENV_READUPDATE      = "READUPDATE"
 	ENV_READRECEIPTS    = "READRECEIPTS"
 	ENV_CALLS           = "CALLS"
 	ENV_GROUPS          = "GROUPS"
 	ENV_BROADCASTS      = "BROADCASTS"
+	ENV_NEWSLETTERS     = "NEWSLETTERS"
+	ENV_PRESENCEEVENTS  = "PRESENCEEVENTS" // post contact presence and typing events to webhooks without "presences" option, default false
 	ENV_HISTORYSYNCDAYS = "HISTORYSYNCDAYS"
 
 	ENV_PRESENCE            = "PRESENCE"
@@ -183,6 +206,11 @@ func (*Environment) Broadcasts() whatsapp.WhatsappBooleanExtended {
 	return ParseWhatsappBoolean(v)
 }
 
+func (*Environment) Newsletters() whatsapp.WhatsappBooleanExtended {
+	v := os.Getenv(ENV_NEWSLETTERS)
+	return ParseWhatsappBoolean(v)
+}
+
 func (*Environment) Groups() whatsapp.WhatsappBooleanExtended {
 	v := os.Getenv(ENV_GROUPS)
 	return ParseWhatsappBoolean(v)
@@ -198,6 +226,12 @@ func (*Environment) Calls() whatsapp.WhatsappBooleanExtended {
 	return ParseWhatsappBoolean(v)
 }
 
+// PRESENCEEVENTS => default false
+func (*Environment) PresenceEvents() bool {
+	value, _ := GetEnvBool(ENV_PRESENCEEVENTS, proto.Bool(false))
+	return *value
+}
+
 func (*Environment) ReadUpdate() bool {
 	value, _ := GetEnvBool(ENV_READUPDATE, proto.Bool(false))
 	return *value
@@ -293,6 +327,132 @@ func (*Environment) CacheDays() uint64 {
 	return 0
 }
 
+// MESSAGESTORE => persists messages on database, surviving restarts, default false
+func (*Environment) MessageStore() bool {
+	value, _ := GetEnvBool(ENV_MESSAGESTORE, proto.Bool(false))
+	return *value
+}
+
+// Days for stored messages, auto-cleaner old ones if bigger than 0
+func (*Environment) MessageStoreDays() uint64 {
+	stringValue, err := GetEnvStr(ENV_MESSAGESTOREDAYS)
+	if err == nil {
+		value, err := strconv.ParseUint(stringValue, 10, 32)
+		if err == nil {
+			return value
+		}
+	}
+
+	return 0
+}
+
+// Length for stored messages per server, auto-cleaner old ones if bigger than 0
+func (*Environment) MessageStoreLength() uint64 {
+	stringValue, err := GetEnvStr(ENV_MESSAGESTORELENGTH)
+	if err == nil {
+		value, err := strconv.ParseUint(stringValue, 10, 32)
+		if err == nil {
+			return value
+		}
+	}
+
+	return 0
+}
+
+//#region WEBHOOK DELIVERIES
+
+// gets an unsigned integer environment variable or default
+func getEnvUint(key string, value uint64) uint64 {
+	stringValue, err := GetEnvStr(key)
+	if err == nil {
+		result, err := strconv.ParseUint(stringValue, 10, 32)
+		if err == nil {
+			return result
+		}
+	}
+
+	return value
+}
+
+// Timeout for webhook responses
+func (*Environment) WebhookTimeout() time.Duration {
+	seconds := getEnvUint(ENV_WEBHOOK_TIMEOUT, 10)
+	return time.Duration(seconds) * time.Second
+}
+
+// Retries for failed webhook deliveries, before moving to dead letters
+func (*Environment) WebhookRetries() uint64 {
+	return getEnvUint(ENV_WEBHOOK_RETRIES, 5)
+}
+
+// Initial interval between webhook delivery retries
+func (*Environment) WebhookBackoff() time.Duration {
+	seconds := getEnvUint(ENV_WEBHOOK_BACKOFF, 5)
+	return time.Duration(seconds) * time.Second
+}
+
+// Maximum interval between webhook delivery retries
+func (*Environment) WebhookBackoffMax() time.Duration {
+	seconds := getEnvUint(ENV_WEBHOOK_BACKOFFMAX, 3600)
+	return time.Duration(seconds) * time.Second
+}
+
+//#endregion
+//#region SEND QUEUE
+
+// SENDQUEUE => enqueue all api sends, scheduled sends (sendat) are always enqueued, default false
+func (*Environment) SendQueue() bool {
+	value, _ := GetEnvBool(ENV_SENDQUEUE, proto.Bool(false))
+	return *value
+}
+
+// Maximum messages per minute per server, zero for unlimited
+func (*Environment) SendQueueRate() uint64 {
+	return getEnvUint(ENV_SENDQUEUE_RATE, 20)
+}
+
+// Minimum random delay between queued sends
+func (*Environment) SendQueueDelayMin() time.Duration {
+	seconds := getEnvUint(ENV_SENDQUEUE_DELAYMIN, 1)
+	return time.Duration(seconds) * time.Second
+}
+
+// Maximum random delay between queued sends
+func (*Environment) SendQueueDelayMax() time.Duration {
+	seconds := getEnvUint(ENV_SENDQUEUE_DELAYMAX, 5)
+	return time.Duration(seconds) * time.Second
+}
+
+// Typing presence duration before each queued send, zero to disable
+func (*Environment) SendQueueTyping() time.Duration {
+	seconds := getEnvUint(ENV_SENDQUEUE_TYPING, 0)
+	return time.Duration(seconds) * time.Second
+}
+
+// Retries for failed queued sends, before marking as failed
+func (*Environment) SendQueueRetries() uint64 {
+	return getEnvUint(ENV_SENDQUEUE_RETRIES, 3)
+}
+
+// Initial interval between queued send retries
+func (*Environment) SendQueueBackoff() time.Duration {
+	seconds := getEnvUint(ENV_SENDQUEUE_BACKOFF, 30)
+	return time.Duration(seconds) * time.Second
+}
+
+//#endregion
+
+// Hours to keep send idempotency keys, zero disables idempotent sends
+func (*Environment) IdempotencyHours() uint64 {
+	return getEnvUint(ENV_IDEMPOTENCYHOURS, 24)
+}
+
+// Minimum interval between auto rule replies or forwards on the same chat, zero disables
+func (*Environment) AutoRulesCooldown() time.Duration {
+	seconds := getEnvUint(ENV_AUTORULES_COOLDOWN, 60)
+	return time.Duration(seconds) * time.Second
+}
+
 // Master Key for super admin methods
 func (*Environment) MasterKey() string {
 	result, _ := GetEnvStr(ENV_MASTER_KEY)
@@ -0,0 +1,177 @@
+package models
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// Event stream types
+const (
+	// cached messages, resumable by id
+	EventTypeMessage = "message"
+
+	// read receipts, not cached
+	EventTypeReceipt = "receipt"
+
+	// presence updates, not cached
+	EventTypePresence = "presence"
+
+	// delivery status of a cached message
+	EventTypeStatus = "status"
+
+	// connection state transitions
+	EventTypeState = "state"
+
+	// events discarded for this subscriber due to slow reading, messages can be replayed from cache
+	EventTypeLagged = "lagged"
+)
+
+// Events kept on each subscriber buffer before discarding new ones
+const EventSubscriberBuffer = 256
+
+type QpEvent struct {
+	// only messages have ids, used to resume streams
+	Id   string      `json:"id,omitempty"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+type QpEventStatus struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type QpEventLagged struct {
+	Dropped uint64 `json:"dropped"`
+}
+
+type QpEventState struct {
+	Token     string    `json:"token"`
+	State     string    `json:"state"`
+	Reason    string    `json:"reason,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type QpEventSubscriber struct {
+	Token  string
+	Events chan *QpEvent
+
+	// events discarded due to slow reading, see TakeLagged
+	Dropped uint64
+
+	// signaled when events are discarded
+	Lagged chan struct{}
+}
+
+// Lagged event with discarded events since last call, reseting the counter, nil if none
+func (source *QpEventSubscriber) TakeLagged() *QpEvent {
+	dropped := atomic.SwapUint64(&source.Dropped, 0)
+	if dropped == 0 {
+		return nil
+	}
+
+	return &QpEvent{Type: EventTypeLagged, Data: &QpEventLagged{Dropped: dropped}}
+}
+
+/*
+<summary>
+
+	Fan out of server events to stream subscribers (sse, websockets), grouped by token
+	Slow subscribers do not block dispatching, exceeding events are discarded and signaled as lagged
+
+</summary>
+*/
+type QpEventHub struct {
+	mutex       sync.RWMutex
+	subscribers map[string]map[*QpEventSubscriber]bool
+}
+
+var EventHub = &QpEventHub{
+	subscribers: map[string]map[*QpEventSubscriber]bool{},
+}
+
+func (source *QpEventHub) Subscribe(token string) *QpEventSubscriber {
+	subscriber := &QpEventSubscriber{
+		Token:  token,
+		Events: make(chan *QpEvent, EventSubscriberBuffer),
+		Lagged: make(chan struct{}, 1),
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if _, ok := source.subscribers[token]; !ok {
+		source.subscribers[token] = map[*QpEventSubscriber]bool{}
+	}
+
+	source.subscribers[token][subscriber] = true
+	return subscriber
+}
+
+func (source *QpEventHub) Unsubscribe(subscriber *QpEventSubscriber) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	if subscribers, ok := source.subscribers[subscriber.Token]; ok {
+		delete(subscribers, subscriber)
+		if len(subscribers) == 0 {
+			delete(source.subscribers, subscriber.Token)
+		}
+	}
+}
+
+// Count of active subscribers for a token
+func (source *QpEventHub) Count(token string) int {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	return len(source.subscribers[token])
+}
+
+// Dispatch an event to all subscribers of a token, never blocks
+func (source *QpEventHub) Publish(token string, event *QpEvent) {
+	if source == nil || event == nil {
+		return
+	}
+
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	for subscriber := range source.subscribers[token] {
+		select {
+		case subscriber.Events <- event:
+		default:
+			atomic.AddUint64(&subscriber.Dropped, 1)
+			select {
+			case subscriber.Lagged <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (source *QpEventHub) PublishMessage(token string, message *whatsapp.WhatsappMessage) {
+	source.Publish(token, &QpEvent{Id: message.Id, Type: EventTypeMessage, Data: message})
+}
+
+// status and state events are also dispatched to signalr connections, messages are dispatched on trigger
+func (source *QpEventHub) PublishStatus(token string, id string, status whatsapp.WhatsappMessageStatus) {
+	event := &QpEvent{Type: EventTypeStatus, Data: &QpEventStatus{Id: id, Status: string(status)}}
+	source.Publish(token, event)
+	go SignalRHub.DispatchEvent(token, event)
+}
+
+func (source *QpEventHub) PublishState(token string, state whatsapp.WhatsappConnectionState, reason string) {
+	data := &QpEventState{
+		Token:     token,
+		State:     state.String(),
+		Reason:    reason,
+		Timestamp: time.Now().UTC(),
+	}
+	event := &QpEvent{Type: EventTypeState, Data: data}
+	source.Publish(token, event)
+	go SignalRHub.DispatchEvent(token, event)
+}
@@ -0,0 +1,29 @@
+package models
+
+type QpGroupCreateRequest struct {
+	Name string `json:"name"`
+
+	// phone numbers or wids, own number is added implicitly
+	Participants []string `json:"participants,omitempty"`
+}
+
+// Group settings to change, only informed ones are applied
+type QpGroupUpdateRequest struct {
+	Name     *string `json:"name,omitempty"`
+	Topic    *string `json:"topic,omitempty"`
+	Announce *bool   `json:"announce,omitempty"`
+	Locked   *bool   `json:"locked,omitempty"`
+}
+
+type QpGroupParticipantsRequest struct {
+	// add, remove, promote or demote
+	Action string `json:"action"`
+
+	// phone numbers or wids
+	Participants []string `json:"participants"`
+}
+
+type QpGroupJoinRequest struct {
+	// invite code or full invite link
+	Code string `json:"code"`
+}
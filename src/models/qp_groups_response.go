@@ -0,0 +1,20 @@
+package models
+
+import whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+
+type QpGroupsResponse struct {
+	QpResponse
+	Total  int                       `json:"total"`
+	Groups []*whatsapp.WhatsappGroup `json:"groups,omitempty"`
+}
+
+type QpGroupResponse struct {
+	QpResponse
+	Group *whatsapp.WhatsappGroup `json:"group,omitempty"`
+}
+
+// Result of participant changes, with error codes for failed ones
+type QpGroupParticipantsResponse struct {
+	QpResponse
+	Participants []*whatsapp.WhatsappGroupParticipant `json:"participants,omitempty"`
+}
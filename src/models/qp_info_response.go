@@ -14,3 +14,20 @@ func (source *QpInfoResponse) PatchSuccess(server *QpWhatsappServer, message str
 	source.QpResponse.ParseSuccess(message)
 	source.Server = server
 }
+
+// Hides the bot token, it bypasses api key scopes, used when responding to api key callers
+func (source *QpInfoResponse) RedactToken() {
+	if source.Server == nil || source.Server.QpServer == nil {
+		return
+	}
+
+	info := *source.Server.QpServer
+	info.Token = ""
+
+	source.Server = &QpWhatsappServer{
+		QpServer:       &info,
+		QpDataWebhooks: QpDataWebhooks{Webhooks: source.Server.Webhooks},
+		Reconnect:      source.Server.Reconnect,
+		StartTime:      source.Server.StartTime,
+	}
+}
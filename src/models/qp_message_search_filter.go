@@ -0,0 +1,105 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Messages search, query is required, other empty fields are ignored
+type QpMessageSearchFilter struct {
+	// terms that must all be present, over text, captions, filenames and contact names
+	Query string
+
+	ChatId string
+	Type   string
+
+	From *time.Time
+	To   *time.Time
+
+	Limit  uint
+	Offset uint
+}
+
+// Words of query, same folding of searchable content, each one matches word prefixes
+func (source *QpMessageSearchFilter) GetTerms() []string {
+	return GetSearchWords(source.Query)
+}
+
+// Lowercase words without diacritics, split on anything but letters and numbers, like sqlite unicode61 tokenizer
+func GetSearchWords(text string) []string {
+	folding := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folding, text)
+	if err != nil {
+		folded = text
+	}
+
+	return strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Used when durable store is not active, scanning cached messages
+func (source *QpMessageSearchFilter) Match(message *whatsapp.WhatsappMessage) bool {
+	if len(source.ChatId) > 0 && message.Chat.Id != source.ChatId {
+		return false
+	}
+
+	if len(source.Type) > 0 && !strings.EqualFold(message.Type.String(), source.Type) {
+		return false
+	}
+
+	if source.From != nil && message.Timestamp.Before(*source.From) {
+		return false
+	}
+
+	if source.To != nil && message.Timestamp.After(*source.To) {
+		return false
+	}
+
+	words := strings.Fields(GetMessageSearchContent(message))
+	for _, term := range source.GetTerms() {
+		if !hasWordPrefix(words, term) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasWordPrefix(words []string, prefix string) bool {
+	for _, word := range words {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Searchable content of a message, text (captions and contact names included), filename and chat titles
+// Stored as folded words (see GetSearchWords), so every search engine matches the same way
+func GetMessageSearchContent(message *whatsapp.WhatsappMessage) string {
+	values := []string{message.Text}
+
+	if message.Attachment != nil {
+		values = append(values, message.Attachment.FileName)
+	}
+
+	values = append(values, message.Chat.Title)
+
+	if message.Participant != nil {
+		values = append(values, message.Participant.Title)
+	}
+
+	var content []string
+	for _, value := range values {
+		content = append(content, GetSearchWords(value)...)
+	}
+
+	return strings.Join(content, " ")
+}
@@ -0,0 +1,6 @@
+package models
+
+type QpNewsletterFollowRequest struct {
+	// newsletter id (@newsletter), invite code or full invite link
+	Id string `json:"id"`
+}
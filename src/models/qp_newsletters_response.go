@@ -0,0 +1,21 @@
+package models
+
+import whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+
+type QpNewslettersResponse struct {
+	QpResponse
+	Total       int                            `json:"total"`
+	Newsletters []*whatsapp.WhatsappNewsletter `json:"newsletters,omitempty"`
+}
+
+type QpNewsletterResponse struct {
+	QpResponse
+	Newsletter *whatsapp.WhatsappNewsletter `json:"newsletter,omitempty"`
+}
+
+// Recent posts of a newsletter, ids are server ids
+type QpNewsletterMessagesResponse struct {
+	QpResponse
+	Total    int                         `json:"total"`
+	Messages []*whatsapp.WhatsappMessage `json:"messages,omitempty"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// Request for setting server presence or sending chat presence
+type QpPresenceRequest struct {
+	// Chat to send chat presence or subscribe, empty for server presence
+	ChatId string `json:"chatid,omitempty"`
+
+	// available, unavailable for server; composing (typing), recording, paused for chats
+	Presence string `json:"presence,omitempty"`
+}
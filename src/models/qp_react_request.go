@@ -0,0 +1,9 @@
+package models
+
+// Request for sending an emoji reaction to a message
+type QpReactRequest struct {
+	MessageId string `json:"messageid"`
+
+	// Emoji to react with, empty to remove a previous reaction
+	Emoji string `json:"emoji,omitempty"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// Request for marking messages as read, by ids or a whole chat
+type QpReadRequest struct {
+	// Cached received messages ids
+	MessageIds []string `json:"messageids,omitempty"`
+
+	// Marks every cached received message of this chat up to now
+	ChatId string `json:"chatid,omitempty"`
+}
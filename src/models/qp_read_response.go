@@ -0,0 +1,7 @@
+package models
+
+type QpReadResponse struct {
+	QpResponse
+	Total int      `json:"total"`
+	Ids   []string `json:"ids,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+
+type QpSearchResponse struct {
+	QpResponse
+	Total    uint                        `json:"total"`
+	Limit    uint                        `json:"limit"`
+	Offset   uint                        `json:"offset"`
+	Messages []*whatsapp.WhatsappMessage `json:"messages,omitempty"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Header used by clients to avoid duplicated sends on retries
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Header included on replayed responses
+const IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+// Reserved keys without response after this time are considered abandoned, ex: crash while sending
+const IdempotencyInProgressTimeout = 2 * time.Minute
+
+var ErrIdempotencyInProgress = errors.New("a request with the same idempotency key is still in progress")
+
+// Reserves a send idempotency key, returns the original response if the key was already used
+func IdempotencyReserve(context string, key string) (previous []byte, err error) {
+	hours := ENV.IdempotencyHours()
+	if hours == 0 || len(key) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	expiration := now.Add(time.Duration(-hours) * time.Hour)
+	pending := now.Add(-IdempotencyInProgressTimeout)
+	previous, reserved, err := WhatsappService.DB.Idempotency.Reserve(context, key, expiration, pending)
+	if err != nil || reserved {
+		return nil, err
+	}
+
+	if len(previous) == 0 {
+		return nil, ErrIdempotencyInProgress
+	}
+
+	return
+}
+
+// Saves the response for a reserved key on success, otherwise releases it for retries
+func IdempotencyFinish(context string, key string, success bool, response []byte) (err error) {
+	if ENV.IdempotencyHours() == 0 || len(key) == 0 {
+		return
+	}
+
+	if success {
+		return WhatsappService.DB.Idempotency.Complete(context, key, response)
+	}
+
+	return WhatsappService.DB.Idempotency.Release(context, key)
+}
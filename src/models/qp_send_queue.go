@@ -0,0 +1,232 @@
+package models
+
+import (
+	"sync"
+	"time"
+
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "github.com/nocodeleaks/quepasa/whatsmeow"
+)
+
+// interval to look up for queued messages when idle or not ready
+const SendQueueIdle time.Duration = 30 * time.Second
+
+// maximum interval between queued send retries
+const SendQueueBackoffMax time.Duration = time.Hour
+
+// Persistent outbound send queue, one per server, with rate limit and human like delays
+type QpSendQueue struct {
+	library.LogStruct // logging
+
+	server *QpWhatsappServer
+	db     QpDataSendQueueInterface
+
+	// earliest time for the next send, rate limit and random delays
+	next time.Time
+
+	signal   chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewQpSendQueue(server *QpWhatsappServer, db QpDataSendQueueInterface) *QpSendQueue {
+	return &QpSendQueue{
+		server: server,
+		db:     db,
+		signal: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Saves a message to be sent at time (zero for now), generating its id if not set
+func (source *QpSendQueue) Enqueue(msg *whatsapp.WhatsappMessage, sendat time.Time) (item *QpSendQueueItem, err error) {
+	if len(msg.Id) == 0 {
+		msg.Id = whatsmeow.GenerateMessageId()
+	}
+
+	if sendat.IsZero() {
+		sendat = time.Now()
+	}
+
+	item, err = NewQpSendQueueItem(source.server.Token, msg, sendat)
+	if err != nil {
+		return
+	}
+
+	err = source.db.Enqueue(item)
+	if err != nil {
+		return
+	}
+
+	source.Notify()
+	return
+}
+
+// Lists queued and failed items
+func (source *QpSendQueue) Items() ([]*QpSendQueueItem, error) {
+	return source.db.FindAll(source.server.Token)
+}
+
+// Removes an item, queued or failed, empty id for all
+func (source *QpSendQueue) Cancel(id string) (uint, error) {
+	return source.db.Remove(source.server.Token, id)
+}
+
+// Wakes up the dispatcher, non blocking
+func (source *QpSendQueue) Notify() {
+	select {
+	case source.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Ends dispatcher loop
+func (source *QpSendQueue) Stop() {
+	source.stopOnce.Do(func() { close(source.stop) })
+}
+
+// Dispatcher loop, should run on its own routine
+func (source *QpSendQueue) Run() {
+	logentry := source.GetLogger()
+	logentry.Debug("starting send queue dispatcher")
+
+	for {
+		wait := source.Dispatch()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-source.stop:
+			timer.Stop()
+			logentry.Debug("stopping send queue dispatcher")
+			return
+		case <-source.signal:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// Sends the next due item if allowed, returns how long to wait before trying again
+func (source *QpSendQueue) Dispatch() time.Duration {
+	logentry := source.GetLogger()
+
+	if source.server.GetStatus() != whatsapp.Ready {
+		return SendQueueIdle
+	}
+
+	now := time.Now().UTC()
+	if now.Before(source.next) {
+		return source.next.Sub(now)
+	}
+
+	item, err := source.db.FindNext(source.server.Token, now)
+	if err != nil {
+		logentry.Errorf("error on getting next queued message: %s", err.Error())
+		return SendQueueIdle
+	}
+
+	// nothing due, waiting for the next scheduled one
+	if item == nil {
+		first, err := source.db.FindFirst(source.server.Token)
+		if err != nil || first == nil {
+			return SendQueueIdle
+		}
+
+		if until := time.Until(first.SendAt); until < SendQueueIdle {
+			return until
+		}
+		return SendQueueIdle
+	}
+
+	source.Send(item)
+	source.next = time.Now().UTC().Add(source.server.SendQueueSettings.GetInterval())
+	return 0
+}
+
+// Sends a queued item, with optional typing presence, removing it on success
+// Failed sends are retried with backoff, then marked as failed
+func (source *QpSendQueue) Send(item *QpSendQueueItem) {
+	logentry := source.GetLogger()
+	loglevel := logentry.Level
+	logentry = logentry.WithField(LogFields.MessageId, item.Id)
+	logentry = logentry.WithField(LogFields.ChatId, item.ChatId)
+	logentry.Level = loglevel
+
+	msg, err := item.ToWhatsappMessage()
+	if err != nil {
+		// invalid stored message, retrying will not help
+		logentry.Warnf("queued message invalid: %s", err.Error())
+		source.Fail(item, err)
+		return
+	}
+
+	source.Typing(msg)
+	_, err = source.server.SendMessage(msg)
+	if err != nil {
+		item.Attempts++
+		if uint64(item.Attempts) > ENV.SendQueueRetries() {
+			logentry.Warnf("queued message send failed after %v attempt(s): %s", item.Attempts, err.Error())
+			source.Fail(item, err)
+			return
+		}
+
+		item.LastError = err.Error()
+		item.SendAt = time.Now().UTC().Add(GetExponentialBackoff(item.Attempts, ENV.SendQueueBackoff(), SendQueueBackoffMax))
+		logentry.Infof("queued message send failed, attempt: %v, retrying at: %s, cause: %s", item.Attempts, item.SendAt, err.Error())
+
+		err = source.db.Update(item)
+		if err != nil {
+			logentry.Errorf("error on updating queued message: %s", err.Error())
+		}
+		return
+	}
+
+	logentry.Infof("queued message sent")
+	_, err = source.db.Remove(item.Context, item.Id)
+	if err != nil {
+		logentry.Errorf("error on removing sent queued message: %s", err.Error())
+	}
+}
+
+// Marks an item as failed, kept until canceled
+func (source *QpSendQueue) Fail(item *QpSendQueueItem, cause error) {
+	item.Status = SendQueueStatusFailed
+	item.LastError = cause.Error()
+	err := source.db.Update(item)
+	if err != nil {
+		logentry := source.GetLogger()
+		logentry.Errorf("error on updating queued message: %s", err.Error())
+	}
+}
+
+// Sends typing (or recording for audios) presence and waits, if enabled
+func (source *QpSendQueue) Typing(msg *whatsapp.WhatsappMessage) {
+	duration := source.server.SendQueueSettings.GetTyping()
+	if duration <= 0 {
+		return
+	}
+
+	conn, err := source.server.GetValidConnection()
+	if err != nil {
+		return
+	}
+
+	presence := whatsapp.WhatsappChatPresenceTyping
+	if msg.Type == whatsapp.AudioMessageType {
+		presence = whatsapp.WhatsappChatPresenceRecording
+	}
+
+	err = conn.SendChatPresence(msg.Chat.Id, presence)
+	if err != nil {
+		source.GetLogger().Warnf("error on sending chat presence: %s", err.Error())
+		return
+	}
+
+	select {
+	case <-source.stop:
+	case <-time.After(duration):
+	}
+
+	conn.SendChatPresence(msg.Chat.Id, whatsapp.WhatsappChatPresencePaused)
+}
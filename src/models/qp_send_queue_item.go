@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+const (
+	SendQueueStatusQueued = "queued"
+	SendQueueStatusFailed = "failed"
+)
+
+// Outbound message waiting on send queue, attachment content is stored apart from message json
+type QpSendQueueItem struct {
+	Id        string          `db:"id" json:"id"`
+	Context   string          `db:"context" json:"-"`
+	ChatId    string          `db:"chatid" json:"chatid"`
+	TrackId   string          `db:"trackid" json:"trackid,omitempty"`
+	Message   json.RawMessage `db:"message" json:"message,omitempty"`
+	Content   []byte          `db:"content" json:"-"`
+	PTT       bool            `db:"ptt" json:"-"`
+	Status    string          `db:"status" json:"status"`
+	LastError string          `db:"lasterror" json:"lasterror,omitempty"`
+	Attempts  uint            `db:"attempts" json:"attempts,omitempty"`
+	SendAt    time.Time       `db:"sendat" json:"sendat"`
+	Timestamp time.Time       `db:"timestamp" json:"timestamp"`
+}
+
+func NewQpSendQueueItem(context string, msg *whatsapp.WhatsappMessage, sendat time.Time) (item *QpSendQueueItem, err error) {
+	message, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	item = &QpSendQueueItem{
+		Id:        msg.Id,
+		Context:   context,
+		ChatId:    msg.Chat.Id,
+		TrackId:   msg.TrackId,
+		Message:   message,
+		Status:    SendQueueStatusQueued,
+		SendAt:    sendat.UTC(),
+		Timestamp: time.Now().UTC(),
+	}
+
+	if msg.Attachment != nil {
+		if content := msg.Attachment.GetContent(); content != nil {
+			item.Content = *content
+		}
+		item.PTT = msg.Attachment.IsPTTCompatible()
+	}
+
+	return
+}
+
+// Restores the whatsapp message, including attachment content
+func (source *QpSendQueueItem) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, err error) {
+	msg = &whatsapp.WhatsappMessage{}
+	err = json.Unmarshal(source.Message, msg)
+	if err != nil {
+		return
+	}
+
+	if msg.Attachment != nil {
+		content := source.Content
+		msg.Attachment.SetContent(&content)
+		msg.Attachment.SetPTTCompatible(source.PTT)
+	}
+
+	return
+}
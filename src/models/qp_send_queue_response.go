@@ -0,0 +1,9 @@
+package models
+
+// Queued messages waiting for send or failed
+type QpSendQueueResponse struct {
+	QpResponse
+	Affected uint               `json:"affected,omitempty"` // items affected
+	Total    int                `json:"total,omitempty"`
+	Items    []*QpSendQueueItem `json:"items,omitempty"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+/*
+<summary>
+
+	Per server send queue settings, empty fields uses env defaults (SENDQUEUE_RATE, SENDQUEUE_DELAYMIN, SENDQUEUE_DELAYMAX and SENDQUEUE_TYPING)
+	* ex: {"rate": 10, "delaymin": 3, "delaymax": 8, "typing": 2}
+
+</summary>
+*/
+type QpSendQueueSettings struct {
+	// maximum messages per minute, 0 for unlimited
+	Rate *uint64 `json:"rate,omitempty"`
+
+	// minimum and maximum random seconds between sends
+	DelayMin *uint64 `json:"delaymin,omitempty"`
+	DelayMax *uint64 `json:"delaymax,omitempty"`
+
+	// seconds of typing presence before each send, 0 to disable
+	Typing *uint64 `json:"typing,omitempty"`
+}
+
+func (source *QpSendQueueSettings) IsEmpty() bool {
+	return source == nil || (source.Rate == nil && source.DelayMin == nil && source.DelayMax == nil && source.Typing == nil)
+}
+
+func (source *QpSendQueueSettings) Validate() error {
+	if source == nil {
+		return nil
+	}
+
+	if source.GetDelayMin() > source.GetDelayMax() {
+		return fmt.Errorf("invalid send queue delays, min: %s, greater than max: %s", source.GetDelayMin(), source.GetDelayMax())
+	}
+
+	return nil
+}
+
+func (source *QpSendQueueSettings) GetRate() uint64 {
+	if source == nil || source.Rate == nil {
+		return ENV.SendQueueRate()
+	}
+	return *source.Rate
+}
+
+func (source *QpSendQueueSettings) GetDelayMin() time.Duration {
+	if source == nil || source.DelayMin == nil {
+		return ENV.SendQueueDelayMin()
+	}
+	return time.Duration(*source.DelayMin) * time.Second
+}
+
+func (source *QpSendQueueSettings) GetDelayMax() time.Duration {
+	if source == nil || source.DelayMax == nil {
+		return ENV.SendQueueDelayMax()
+	}
+	return time.Duration(*source.DelayMax) * time.Second
+}
+
+func (source *QpSendQueueSettings) GetTyping() time.Duration {
+	if source == nil || source.Typing == nil {
+		return ENV.SendQueueTyping()
+	}
+	return time.Duration(*source.Typing) * time.Second
+}
+
+// Interval between sends, the biggest of rate limit and a random delay between min and max
+func (source *QpSendQueueSettings) GetInterval() time.Duration {
+	var interval time.Duration
+	if rate := source.GetRate(); rate > 0 {
+		interval = time.Minute / time.Duration(rate)
+	}
+
+	minimum := source.GetDelayMin()
+	maximum := source.GetDelayMax()
+
+	delay := minimum
+	if maximum > minimum {
+		delay += time.Duration(rand.Int63n(int64(maximum - minimum)))
+	}
+
+	if delay > interval {
+		return delay
+	}
+	return interval
+}
+
+//#region DATABASE JSON COLUMN
+
+func (source *QpSendQueueSettings) Value() (driver.Value, error) {
+	if source.IsEmpty() {
+		return nil, nil
+	}
+
+	return json.Marshal(source)
+}
+
+func (source *QpSendQueueSettings) Scan(value interface{}) error {
+	return scanJsonColumn(value, source)
+}
+
+//#endregion
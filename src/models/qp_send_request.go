@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	log "github.com/sirupsen/logrus"
@@ -39,6 +40,21 @@ type QpSendRequest struct {
 	// (Optional) time in seconds for audio/video contents
 	Seconds uint32 `json:"seconds,omitempty"`
 
+	// (Optional) poll with question and options, text is used as question if not set
+	Poll *whatsapp.WhatsappPoll `json:"poll,omitempty"`
+
+	// (Optional) list menu with sections and rows
+	List *whatsapp.WhatsappList `json:"list,omitempty"`
+
+	// (Optional) quick reply buttons
+	Buttons *whatsapp.WhatsappButtons `json:"buttons,omitempty"`
+
+	// (Optional) status (stories) only, publishes just for these contacts instead of privacy settings audience
+	Recipients []string `json:"recipients,omitempty"`
+
+	// (Optional) schedule delivery through send queue, RFC3339 on json, also unix seconds on parameters
+	SendAt *time.Time `json:"sendat,omitempty"`
+
 	Content []byte
 }
 
@@ -96,11 +112,96 @@ func (source *QpSendRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage,
 		FromInternal: true,
 	}
 
+	err = source.ToStatusRecipients(msg)
+	if err != nil {
+		return
+	}
+
 	// setting default type
 	if len(msg.Text) > 0 {
 		msg.Type = whatsapp.TextMessageType
 	}
 
+	err = source.ToInteractiveMessage(msg)
+	return
+}
+
+// Validates and sets status recipients on message, only user contacts are allowed
+func (source *QpSendRequest) ToStatusRecipients(msg *whatsapp.WhatsappMessage) (err error) {
+	if len(source.Recipients) == 0 {
+		return
+	}
+
+	if msg.Chat.Id != whatsapp.WhatsappStatusChatId {
+		return fmt.Errorf("recipients are allowed only on status (stories)")
+	}
+
+	for _, recipient := range source.Recipients {
+		wid, err := whatsapp.FormatEndpoint(recipient)
+		if err != nil {
+			return err
+		}
+
+		if !strings.HasSuffix(wid, "@s.whatsapp.net") {
+			return fmt.Errorf("invalid status recipient, only contacts are allowed: %s", recipient)
+		}
+
+		msg.Recipients = append(msg.Recipients, wid)
+	}
+
+	return
+}
+
+// Indicates that this request has a poll, list or buttons to send
+func (source *QpSendRequest) IsInteractive() bool {
+	return source.Poll != nil || source.List != nil || source.Buttons != nil
+}
+
+// Validates and sets poll, list or buttons on message, only one is allowed
+func (source *QpSendRequest) ToInteractiveMessage(msg *whatsapp.WhatsappMessage) (err error) {
+	count := 0
+	for _, informed := range []bool{source.Poll != nil, source.List != nil, source.Buttons != nil} {
+		if informed {
+			count++
+		}
+	}
+
+	if count > 1 {
+		return fmt.Errorf("only one of poll, list or buttons can be sent at once")
+	}
+
+	switch {
+	case source.Poll != nil:
+		if len(source.Poll.Question) == 0 {
+			source.Poll.Question = source.Text
+		}
+
+		err = source.Poll.Validate()
+		msg.Type = whatsapp.PollMessageType
+		msg.Text = source.Poll.Question
+		msg.Poll = source.Poll
+
+	case source.List != nil:
+		if len(source.List.Text) == 0 {
+			source.List.Text = source.Text
+		}
+
+		err = source.List.Validate()
+		msg.Type = whatsapp.ListMessageType
+		msg.Text = source.List.Text
+		msg.List = source.List
+
+	case source.Buttons != nil:
+		if len(source.Buttons.Text) == 0 {
+			source.Buttons.Text = source.Text
+		}
+
+		err = source.Buttons.Validate()
+		msg.Type = whatsapp.ButtonsMessageType
+		msg.Text = source.Buttons.Text
+		msg.Buttons = source.Buttons
+	}
+
 	return
 }
 
@@ -140,6 +241,25 @@ func (source *QpSendRequest) ToWhatsappAttachment() (result QpToWhatsappAttachme
 	return
 }
 
+// Parses schedule from text, RFC3339 or unix seconds
+func ParseSendAt(value string) (*time.Time, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		result := time.Unix(seconds, 0).UTC()
+		return &result, nil
+	}
+
+	result, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sendat, use RFC3339 or unix seconds: %s", value)
+	}
+
+	return &result, nil
+}
+
 // From "body" content (sendbinary)
 func (source *QpSendRequest) GenerateBodyContent(r *http.Request) (err error) {
 	content, err := io.ReadAll(r.Body)
@@ -1,8 +1,11 @@
 package models
 
+import "time"
+
 type QpSendResponseMessage struct {
-	Id      string `json:"id,omitempty"`
-	Wid     string `json:"wid,omitempty"`
-	ChatId  string `json:"chatId,omitempty"`
-	TrackId string `json:"trackId,omitempty"`
+	Id      string     `json:"id,omitempty"`
+	Wid     string     `json:"wid,omitempty"`
+	ChatId  string     `json:"chatId,omitempty"`
+	TrackId string     `json:"trackId,omitempty"`
+	SendAt  *time.Time `json:"sendAt,omitempty"` // when enqueued, scheduled time
 }
@@ -7,6 +7,9 @@ type QpSendResponseV2 struct {
 	From QPEndpointV2 `json:"from,omitempty"`
 	Chat QPEndpointV2 `json:"chat,omitempty"`
 
+	// enqueued on send queue, not sent yet
+	Queued bool `json:"queued,omitempty"`
+
 	// Para compatibilidade apenas
 	PreviusV1 QPSendResult `json:"result,omitempty"`
 }
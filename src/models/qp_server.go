@@ -28,7 +28,17 @@ type QpServer struct {
 	Verified bool   `db:"verified" json:"verified"`
 	Devel    bool   `db:"devel" json:"devel"`
 
-	User      string    `db:"user" json:"user,omitempty" validate:"max=36"`
+	User string `db:"user" json:"user,omitempty" validate:"max=36"`
+
+	// Rules for incoming calls, as reject and reply outside business hours
+	CallRules *QpCallRules `db:"callrules" json:"callrules,omitempty"`
+
+	// Send queue rate, delays and typing, empty for env defaults
+	SendQueueSettings *QpSendQueueSettings `db:"sendqueue" json:"sendqueue,omitempty"`
+
+	// Chatwoot channel settings, not exposed because of access token, see "/chatwoot"
+	Chatwoot *QpChatwootConfig `db:"chatwoot" json:"-"`
+
 	Timestamp time.Time `db:"timestamp" json:"timestamp,omitempty"`
 }
 
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "github.com/nocodeleaks/quepasa/whatsmeow"
+)
+
+// Durable message model, persisted by message stores
+type QpServerMessage struct {
+	Context     string    `db:"context" json:"context"`
+	Id          string    `db:"id" json:"id"`
+	ChatId      string    `db:"chatid" json:"chatid"`
+	Participant string    `db:"participant" json:"participant,omitempty"`
+	Type        string    `db:"type" json:"type"`
+	Status      string    `db:"status" json:"status,omitempty"`
+	Text        string    `db:"text" json:"text,omitempty"`
+	FromMe      bool      `db:"fromme" json:"fromme"`
+	Payload     []byte    `db:"payload" json:"-"` // json of whatsapp message
+	Content     []byte    `db:"content" json:"-"` // original message from source service
+	Info        []byte    `db:"info" json:"-"`    // original message info from source service
+	Search      string    `db:"search" json:"-"`  // searchable content, text, filename and contact names
+	Timestamp   time.Time `db:"timestamp" json:"timestamp"`
+}
+
+// Generates a durable message from a whatsapp message for a specific server (context)
+func NewQpServerMessage(context string, msg *whatsapp.WhatsappMessage) (element *QpServerMessage, err error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	content, info, err := whatsmeow.MarshalMessageSource(msg)
+	if err != nil {
+		return
+	}
+
+	element = &QpServerMessage{
+		Context:     context,
+		Id:          strings.ToUpper(msg.Id),
+		ChatId:      msg.Chat.Id,
+		Participant: msg.GetParticipantId(),
+		Type:        msg.Type.String(),
+		Status:      string(msg.Status),
+		Text:        msg.Text,
+		FromMe:      msg.FromMe,
+		Payload:     payload,
+		Content:     content,
+		Info:        info,
+		Search:      GetMessageSearchContent(msg),
+		Timestamp:   msg.Timestamp.UTC(),
+	}
+	return
+}
+
+// Restores the whatsapp message, including original content if available
+func (source *QpServerMessage) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, err error) {
+	msg = &whatsapp.WhatsappMessage{}
+	err = json.Unmarshal(source.Payload, msg)
+	if err != nil {
+		return
+	}
+
+	// status could be updated after payload was saved
+	if len(source.Status) > 0 {
+		msg.Status = whatsapp.WhatsappMessageStatus(source.Status)
+	}
+
+	err = whatsmeow.UnmarshalMessageSource(msg, source.Content, source.Info)
+	return
+}
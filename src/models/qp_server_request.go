@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// informed tokens are used on urls and headers, same limit of database column
+const (
+	ServerTokenMinLength = 8
+	ServerTokenMaxLength = 100
+)
+
+var RegexValidServerToken = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Checks length and allowed characters (letters, digits, dot, underscore and hyphen)
+func ValidateServerToken(token string) error {
+	if len(token) < ServerTokenMinLength || len(token) > ServerTokenMaxLength {
+		return fmt.Errorf("invalid token length, must have between %v and %v characters", ServerTokenMinLength, ServerTokenMaxLength)
+	}
+
+	if !RegexValidServerToken.MatchString(token) {
+		return fmt.Errorf("invalid token format, only letters, digits, dot, underscore and hyphen are allowed")
+	}
+
+	return nil
+}
+
+type QpServerCreateRequest struct {
+	whatsapp.WhatsappOptions
+
+	// optional, generated if empty
+	Token string `json:"token,omitempty"`
+
+	// optional owner username
+	User string `json:"user,omitempty"`
+
+	Devel bool `json:"devel,omitempty"`
+}
+
+func (source *QpServerCreateRequest) ToQpServer() *QpServer {
+	return &QpServer{
+		WhatsappOptions: source.WhatsappOptions,
+		Token:           source.Token,
+		User:            source.User,
+		Devel:           source.Devel,
+	}
+}
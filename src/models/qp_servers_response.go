@@ -0,0 +1,27 @@
+package models
+
+// Server settings with live connection status
+type QpServerStatus struct {
+	*QpServer
+	Status string `json:"status"`
+	Number string `json:"number,omitempty"`
+}
+
+func NewQpServerStatus(server *QpWhatsappServer) *QpServerStatus {
+	return &QpServerStatus{
+		QpServer: server.QpServer,
+		Status:   server.GetStatus().String(),
+		Number:   server.GetNumber(),
+	}
+}
+
+type QpServersResponse struct {
+	QpResponse
+	Total   int               `json:"total"`
+	Servers []*QpServerStatus `json:"servers,omitempty"`
+}
+
+type QpServerResponse struct {
+	QpResponse
+	Server *QpServerStatus `json:"server,omitempty"`
+}
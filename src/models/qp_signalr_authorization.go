@@ -0,0 +1,61 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type signalRAuthorizationKey struct{}
+
+/*
+<summary>
+
+	Credentials validated at signalr negotiate, attached to the connection context
+	* master key: may subscribe to any server, receives all by default
+	* api key: may subscribe to servers allowed for the user, with receive scope
+	* bot token: subscribed to its own server only
+
+</summary>
+*/
+type QpSignalRAuthorization struct {
+	Master bool
+	ApiKey *QpApiAuthorization
+	Token  string
+}
+
+// Checks that this connection can receive events from a server token
+func (source *QpSignalRAuthorization) CanSubscribe(token string) error {
+	if source == nil {
+		return fmt.Errorf("connection not authenticated")
+	}
+
+	if source.Master {
+		_, err := GetServerFromToken(token)
+		return err
+	}
+
+	if source.ApiKey != nil {
+		_, err := source.ApiKey.GetServer(token, ApiScopeReceive)
+		return err
+	}
+
+	if len(source.Token) > 0 && strings.EqualFold(source.Token, token) {
+		return nil
+	}
+
+	return fmt.Errorf("token not allowed for this connection: %s", token)
+}
+
+func WithSignalRAuthorization(ctx context.Context, authorization *QpSignalRAuthorization) context.Context {
+	return context.WithValue(ctx, signalRAuthorizationKey{}, authorization)
+}
+
+func GetSignalRAuthorizationFromContext(ctx context.Context) *QpSignalRAuthorization {
+	if ctx == nil {
+		return nil
+	}
+
+	authorization, _ := ctx.Value(signalRAuthorizationKey{}).(*QpSignalRAuthorization)
+	return authorization
+}
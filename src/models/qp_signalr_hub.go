@@ -2,95 +2,249 @@ package models
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	signalr "github.com/philippseith/signalr"
 )
 
-type QpSignalRHub struct {
-	signalr.Hub
-	tokens map[string]string
-	proxy  map[string]signalr.ClientProxy
+// Connection registered on signalr hub, with its subscribed tokens
+type QpSignalRConnection struct {
+	Id            string
+	Authorization *QpSignalRAuthorization
+
+	proxy  signalr.ClientProxy
+	tokens map[string]bool
+
+	// master connections receiving events from all servers
+	all bool
 }
 
-var SignalRHub = &QpSignalRHub{
-	tokens: map[string]string{},
-	proxy:  map[string]signalr.ClientProxy{},
+/*
+<summary>
+
+	Registry of signalr connections, safe for concurrent connects and dispatches
+	Events are sent only to connections subscribed to the server token
+
+</summary>
+*/
+type QpSignalRHub struct {
+	mutex       sync.RWMutex
+	connections map[string]*QpSignalRConnection
 }
 
-// not used
-func SignalRHubFactory() signalr.HubInterface {
-	return SignalRHub
+var SignalRHub = &QpSignalRHub{
+	connections: map[string]*QpSignalRConnection{},
 }
 
 func (source *QpSignalRHub) IsInterfaceNil() bool {
 	return source == nil
 }
 
-func (source *QpSignalRHub) OnConnected(ConnectionId string) {
-	info, _ := source.Logger()
-	info.Log("connection", ConnectionId, "status", "connected")
+// Adds a connection, subscribing bot tokens to its own server and master key to all
+func (source *QpSignalRHub) Register(ConnectionId string, authorization *QpSignalRAuthorization, proxy signalr.ClientProxy) *QpSignalRConnection {
+	connection := &QpSignalRConnection{
+		Id:            ConnectionId,
+		Authorization: authorization,
+		proxy:         proxy,
+		tokens:        map[string]bool{},
+		all:           authorization.Master,
+	}
 
-	source.proxy[ConnectionId] = source.Clients().Caller()
-}
+	if len(authorization.Token) > 0 {
+		connection.tokens[strings.ToLower(authorization.Token)] = true
+	}
 
-func (source *QpSignalRHub) OnDisconnected(ConnectionId string) {
-	info, _ := source.Logger()
-	info.Log("connection", ConnectionId, "status", "disconnected")
+	source.mutex.Lock()
+	source.connections[ConnectionId] = connection
+	source.mutex.Unlock()
 
-	delete(source.tokens, ConnectionId)
-	delete(source.proxy, ConnectionId)
+	return connection
 }
 
-func (source *QpSignalRHub) TrySend(ConnectionId string, target string, args ...interface{}) {
-	if source == nil {
-		return
+func (source *QpSignalRHub) UnRegister(ConnectionId string) {
+	source.mutex.Lock()
+	delete(source.connections, ConnectionId)
+	source.mutex.Unlock()
+}
+
+/*
+<summary>
+
+	Subscribes a connection to a server token, checking the connection authorization
+	Master connections subscribing to the master key receives all servers again
+
+</summary>
+*/
+func (source *QpSignalRHub) Subscribe(ConnectionId string, token string) error {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	connection, ok := source.connections[ConnectionId]
+	if !ok {
+		return fmt.Errorf("connection not registered: %s", ConnectionId)
+	}
+
+	masterkey := ENV.MasterKey()
+	if connection.Authorization.Master && len(masterkey) > 0 && strings.EqualFold(masterkey, token) {
+		connection.all = true
+		return nil
 	}
 
-	proxy := source.proxy[ConnectionId]
-	if proxy != nil {
-		proxy.Send(target, args...)
+	err := connection.Authorization.CanSubscribe(token)
+	if err != nil {
+		return err
 	}
+
+	// master connections choosing specific servers
+	connection.all = false
+	connection.tokens[strings.ToLower(token)] = true
+	return nil
 }
 
-func (source *QpSignalRHub) GetToken() string {
-	ConnectionId := source.ConnectionID()
-	token := source.tokens[ConnectionId]
+func (source *QpSignalRHub) UnSubscribe(ConnectionId string, token string) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
 
-	message := fmt.Sprintf("connection id: %s, token: %s", ConnectionId, token)
-	source.Clients().Caller().Send(ConnectionId, "system", message)
-	return token
+	if connection, ok := source.connections[ConnectionId]; ok {
+		delete(connection.tokens, strings.ToLower(token))
+	}
 }
 
-func (source *QpSignalRHub) Token(token string) {
-	ConnectionId := source.ConnectionID()
-	source.tokens[ConnectionId] = token
+// Subscribed tokens of a connection, "*" for all servers
+func (source *QpSignalRHub) GetTokens(ConnectionId string) (tokens []string) {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
 
-	info, _ := source.Logger()
-	info.Log("connection", ConnectionId, "token", token)
+	connection, ok := source.connections[ConnectionId]
+	if !ok {
+		return
+	}
+
+	if connection.all {
+		return []string{"*"}
+	}
+
+	for token := range connection.tokens {
+		tokens = append(tokens, token)
+	}
+
+	sort.Strings(tokens)
+	return
 }
 
+// Connections subscribed to a token, or receiving all servers
 func (source *QpSignalRHub) GetActiveConnections(token string) (active []string) {
-	if source != nil {
-		masterkey := ENV.MasterKey()
-		for ConnectionId, _token := range source.tokens {
-			if strings.EqualFold(masterkey, _token) || _token == token {
-				active = append(active, ConnectionId)
-			}
+	if source == nil {
+		return
+	}
+
+	token = strings.ToLower(token)
+
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+
+	for ConnectionId, connection := range source.connections {
+		if connection.all || connection.tokens[token] {
+			active = append(active, ConnectionId)
 		}
 	}
 
 	return
 }
 
+func (source *QpSignalRHub) TrySend(ConnectionId string, target string, args ...interface{}) {
+	if source == nil {
+		return
+	}
+
+	source.mutex.RLock()
+	connection, ok := source.connections[ConnectionId]
+	source.mutex.RUnlock()
+
+	if ok && connection.proxy != nil {
+		connection.proxy.Send(target, args...)
+	}
+}
+
 func (source *QpSignalRHub) Dispatch(token string, payload *whatsapp.WhatsappMessage) {
 	for _, ConnectionId := range source.GetActiveConnections(token) {
 		source.TrySend(ConnectionId, "message", payload)
 	}
 }
 
+// Dispatches status and connection state events, target is the event type
+func (source *QpSignalRHub) DispatchEvent(token string, event *QpEvent) {
+	for _, ConnectionId := range source.GetActiveConnections(token) {
+		source.TrySend(ConnectionId, event.Type, event.Data)
+	}
+}
+
 func (source *QpSignalRHub) HasActiveConnections(token string) bool {
 	connections := source.GetActiveConnections(token)
 	return len(connections) > 0
 }
+
+//#region CLIENT HUB
+
+// Hub created for each client invocation, state is kept on SignalRHub registry
+type QpSignalRClientHub struct {
+	signalr.Hub
+}
+
+func SignalRHubFactory() signalr.HubInterface {
+	return &QpSignalRClientHub{}
+}
+
+func (source *QpSignalRClientHub) OnConnected(ConnectionId string) {
+	info, _ := source.Logger()
+
+	authorization := GetSignalRAuthorizationFromContext(source.Context())
+	if authorization == nil {
+		info.Log("connection", ConnectionId, "status", "unauthorized")
+		source.Abort()
+		return
+	}
+
+	info.Log("connection", ConnectionId, "status", "connected")
+	SignalRHub.Register(ConnectionId, authorization, source.Clients().Caller())
+}
+
+func (source *QpSignalRClientHub) OnDisconnected(ConnectionId string) {
+	info, _ := source.Logger()
+	info.Log("connection", ConnectionId, "status", "disconnected")
+
+	SignalRHub.UnRegister(ConnectionId)
+}
+
+// Subscribes this connection to a server token, allowed by negotiate credentials
+func (source *QpSignalRClientHub) Token(token string) {
+	ConnectionId := source.ConnectionID()
+
+	err := SignalRHub.Subscribe(ConnectionId, token)
+	if err != nil {
+		source.Clients().Caller().Send("system", fmt.Sprintf("subscribe error: %s", err.Error()))
+		return
+	}
+
+	info, _ := source.Logger()
+	info.Log("connection", ConnectionId, "subscribed", token)
+}
+
+// Removes a server token subscription from this connection
+func (source *QpSignalRClientHub) UnToken(token string) {
+	SignalRHub.UnSubscribe(source.ConnectionID(), token)
+}
+
+func (source *QpSignalRClientHub) GetToken() string {
+	ConnectionId := source.ConnectionID()
+	tokens := strings.Join(SignalRHub.GetTokens(ConnectionId), ",")
+
+	message := fmt.Sprintf("connection id: %s, token: %s", ConnectionId, tokens)
+	source.Clients().Caller().Send("system", message)
+	return tokens
+}
+
+//#endregion
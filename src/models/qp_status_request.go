@@ -0,0 +1,6 @@
+package models
+
+// Request for publishing a status (stories), text or attachment as on send, optionally to recipients only
+type QpStatusRequest struct {
+	QpSendAnyRequest
+}
@@ -0,0 +1,12 @@
+package models
+
+import (
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+type QpStatusResponse struct {
+	QpResponse
+	Total    int                             `json:"total,omitempty"`
+	Messages []*whatsapp.WhatsappMessage     `json:"messages,omitempty"`
+	Privacy  *whatsapp.WhatsappStatusPrivacy `json:"privacy,omitempty"`
+}
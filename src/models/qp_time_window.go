@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Week days and daily time window, used by call and auto reply rules
+type QpTimeWindow struct {
+	// week days, 0 (sunday) to 6 (saturday), empty for every day
+	Days []time.Weekday `json:"days,omitempty"`
+
+	// time window "HH:MM", empty for all day, end before start crosses midnight
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+
+	// applies outside days and time window instead of inside
+	Outside bool `json:"outside,omitempty"`
+
+	// IANA time zone for days and window, default server local
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// checks days, times and time zone
+func (source *QpTimeWindow) Validate() error {
+	for _, day := range source.Days {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("invalid day: %v, try 0 (sunday) to 6 (saturday)", int(day))
+		}
+	}
+
+	if (len(source.Start) == 0) != (len(source.End) == 0) {
+		return fmt.Errorf("start and end must be informed together")
+	}
+
+	for _, value := range []string{source.Start, source.End} {
+		if len(value) > 0 {
+			if _, err := time.Parse("15:04", value); err != nil {
+				return fmt.Errorf("invalid time: %s, try HH:MM", value)
+			}
+		}
+	}
+
+	if _, err := time.LoadLocation(source.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %s", source.Timezone)
+	}
+
+	return nil
+}
+
+// indicates that the rule applies at time, considering outside option
+func (source *QpTimeWindow) Match(now time.Time) bool {
+	return source.InWindow(now) != source.Outside
+}
+
+// indicates that time is inside days and time window
+func (source *QpTimeWindow) InWindow(now time.Time) bool {
+	location, err := time.LoadLocation(source.Timezone)
+	if err == nil {
+		now = now.In(location)
+	}
+
+	if len(source.Days) > 0 {
+		found := false
+		for _, day := range source.Days {
+			if day == now.Weekday() {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if len(source.Start) == 0 || len(source.End) == 0 {
+		return true
+	}
+
+	start, _ := time.Parse("15:04", source.Start)
+	end, _ := time.Parse("15:04", source.End)
+
+	current := now.Hour()*60 + now.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	// crossing midnight
+	if to < from {
+		return current >= from || current < to
+	}
+
+	return current >= from && current < to
+}
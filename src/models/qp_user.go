@@ -5,7 +5,39 @@ import (
 )
 
 type QpUser struct {
-	Username  string    `db:"username" json:"username" validate:"max=255"`
-	Password  string    `db:"password" json:"password" validate:"max=255"`
+	Username string `db:"username" json:"username" validate:"max=255"`
+	Password string `db:"password" json:"password" validate:"max=255"`
+
+	// admin, operator or readonly, limits api keys scopes and servers
+	Role string `db:"role" json:"role,omitempty"`
+
 	Timestamp time.Time `db:"timestamp" json:"timestamp,omitempty"`
 }
+
+// role, default operator
+func (source *QpUser) GetRole() string {
+	if source == nil || len(source.Role) == 0 {
+		return UserRoleOperator
+	}
+	return source.Role
+}
+
+func (source *QpUser) IsAdmin() bool {
+	return source.GetRole() == UserRoleAdmin
+}
+
+// Public view, without password
+type QpUserInfo struct {
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Servers   []string  `json:"servers,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+func (source *QpUser) GetInfo() *QpUserInfo {
+	return &QpUserInfo{
+		Username:  source.Username,
+		Role:      source.GetRole(),
+		Timestamp: source.Timestamp,
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type QpUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	Role     string `json:"role,omitempty"`
+}
+
+type QpUserServerRequest struct {
+	Token string `json:"token"`
+}
+
+type QpApiKeyRequest struct {
+	Name string `json:"name,omitempty"`
+
+	// default all scopes allowed by user role
+	Scopes []string `json:"scopes,omitempty"`
+
+	// optional server token
+	Server string `json:"server,omitempty"`
+
+	Expires *time.Time `json:"expires,omitempty"`
+}
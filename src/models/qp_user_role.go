@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// User roles
+const (
+	// all servers and scopes, manages users and keys
+	UserRoleAdmin = "admin"
+
+	// owned and assigned servers, all scopes
+	UserRoleOperator = "operator"
+
+	// owned and assigned servers, receive scope only
+	UserRoleReadOnly = "readonly"
+)
+
+// Api key scopes
+const (
+	// send, edit, revoke, react and read messages, group and presence changes
+	ApiScopeSend = "send"
+
+	// receive, download and get messages, contacts, groups and server info
+	ApiScopeReceive = "receive"
+
+	// manage webhooks and deliveries
+	ApiScopeWebhooks = "webhooks"
+
+	// pairing, server options, rules, integrations and users
+	ApiScopeManage = "manage"
+)
+
+var ApiScopes = []string{ApiScopeSend, ApiScopeReceive, ApiScopeWebhooks, ApiScopeManage}
+
+// Scopes allowed for each role
+var UserRoleScopes = map[string][]string{
+	UserRoleAdmin:    ApiScopes,
+	UserRoleOperator: ApiScopes,
+	UserRoleReadOnly: {ApiScopeReceive},
+}
+
+func IsValidUserRole(role string) bool {
+	_, ok := UserRoleScopes[role]
+	return ok
+}
+
+// formats and validates an user role, default operator
+func FormatUserRole(role string) (string, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if len(role) == 0 {
+		return UserRoleOperator, nil
+	}
+
+	if !IsValidUserRole(role) {
+		return role, fmt.Errorf("invalid role: {%s}, try {admin,operator,readonly}", role)
+	}
+
+	return role, nil
+}
+
+func IsValidApiScope(scope string) bool {
+	for _, item := range ApiScopes {
+		if item == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// indicates that the role allows the scope
+func UserRoleHasScope(role string, scope string) bool {
+	for _, item := range UserRoleScopes[role] {
+		if item == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package models
+
+type QpUsersResponse struct {
+	QpResponse
+	Total int           `json:"total"`
+	Users []*QpUserInfo `json:"users,omitempty"`
+}
+
+type QpUserResponse struct {
+	QpResponse
+	User *QpUserInfo `json:"user,omitempty"`
+}
+
+type QpApiKeysResponse struct {
+	QpResponse
+	Total int         `json:"total"`
+	Keys  []*QpApiKey `json:"keys,omitempty"`
+
+	// plain key, only returned on creation
+	Key string `json:"key,omitempty"`
+}
@@ -21,13 +21,16 @@ type QpWebhook struct {
 	// ------------------------
 	whatsapp.WhatsappOptions
 
-	Url             string      `db:"url" json:"url,omitempty"`                         // destination
-	ForwardInternal bool        `db:"forwardinternal" json:"forwardinternal,omitempty"` // forward internal msg from api
-	TrackId         string      `db:"trackid" json:"trackid,omitempty"`                 // identifier of remote system to avoid loop
-	Extra           interface{} `db:"extra" json:"extra,omitempty"`                     // extra info to append on payload
-	Failure         *time.Time  `json:"failure,omitempty"`                              // first failure timestamp
-	Success         *time.Time  `json:"success,omitempty"`                              // last success timestamp
-	Timestamp       *time.Time  `db:"timestamp" json:"timestamp,omitempty"`
+	Url             string            `db:"url" json:"url,omitempty"`                         // destination
+	ForwardInternal bool              `db:"forwardinternal" json:"forwardinternal,omitempty"` // forward internal msg from api
+	TrackId         string            `db:"trackid" json:"trackid,omitempty"`                 // identifier of remote system to avoid loop
+	Extra           interface{}       `db:"extra" json:"extra,omitempty"`                     // extra info to append on payload
+	Secret          string            `db:"secret" json:"secret,omitempty"`                   // key for payload hmac signature
+	Filters         *QpWebhookFilters `db:"filters" json:"filters,omitempty"`                 // event types and chats filters
+	Presences       *bool             `db:"presences" json:"presences,omitempty"`             // post presence and typing events, default from env
+	Failure         *time.Time        `json:"failure,omitempty"`                              // first failure timestamp
+	Success         *time.Time        `json:"success,omitempty"`                              // last success timestamp
+	Timestamp       *time.Time        `db:"timestamp" json:"timestamp,omitempty"`
 
 	// just for logging and response headers
 	Wid string `json:"-"`
@@ -91,43 +94,82 @@ func (source QpWebhook) IsSetExtra() bool {
 	return source.Extra != nil
 }
 
+func (source QpWebhook) IsSetFilters() bool {
+	return !source.Filters.IsEmpty()
+}
+
+func (source QpWebhook) GetFiltersText() string {
+	if source.Filters.IsEmpty() {
+		return ""
+	}
+
+	content, _ := json.Marshal(source.Filters)
+	return string(content)
+}
+
+func (source QpWebhook) IsSetSecret() bool {
+	return len(source.Secret) > 0
+}
+
+// presence and typing events are high volume, only posted when enabled
+func (source QpWebhook) HandlePresences() bool {
+	if source.Presences != nil {
+		return *source.Presences
+	}
+	return ENV.PresenceEvents()
+}
+
 //#endregion
 
 var ErrInvalidResponse error = errors.New("the requested url do not return 200 status code")
 
 func (source *QpWebhook) Post(message *whatsapp.WhatsappMessage) (err error) {
+	payloadJson, err := source.GetPayload(message)
+	if err != nil {
+		return
+	}
 
-	// updating log
-	logentry := source.LogWithField(LogFields.MessageId, message.Id)
-	logentry.Infof("posting webhook")
+	return source.PostPayload(message.Id, payloadJson)
+}
 
+// Generates the json body that will be posted, including extra content
+func (source *QpWebhook) GetPayload(message *whatsapp.WhatsappMessage) ([]byte, error) {
 	payload := &QpWebhookPayload{
 		WhatsappMessage: message,
 		Extra:           source.Extra,
 	}
 
-	payloadJson, err := json.Marshal(&payload)
-	if err != nil {
-		return
+	return json.Marshal(&payload)
+}
+
+// Posts an already generated json body, updating failure and success timestamps
+func (source *QpWebhook) PostPayload(messageId string, payloadJson []byte) (err error) {
+
+	// updating log
+	logentry := source.LogWithField(LogFields.MessageId, messageId)
+
+	headers := map[string]string{
+		"User-Agent":    "Quepasa",
+		"X-QUEPASA-WID": source.Wid,
+		"Content-Type":  "application/json",
 	}
 
-	req, err := http.NewRequest("POST", source.Url, bytes.NewBuffer(payloadJson))
-	req.Header.Set("User-Agent", "Quepasa")
-	req.Header.Set("X-QUEPASA-WID", source.Wid)
-	req.Header.Set("Content-Type", "application/json")
+	// signing payload, receivers can verify with library.VerifyWebhookSignature
+	if len(source.Secret) > 0 {
+		signature := library.GenerateWebhookSignatureHeader(source.Secret, time.Now().Unix(), payloadJson)
+		headers[library.WebhookSignatureHeader] = signature
+	}
 
-	client := &http.Client{}
-	client.Timeout = time.Second * 10
-	resp, err := client.Do(req)
-	if err != nil {
-		logentry.Warnf("error at post webhook: %s", err.Error())
+	if IsBrokerUrl(source.Url) {
+		logentry.Infof("publishing webhook on broker")
+		err = PublishToBroker(source.Url, messageId, payloadJson, headers)
+	} else {
+		logentry.Infof("posting webhook")
+		err = source.postHttp(payloadJson, headers)
 	}
 
-	if resp != nil {
-		defer resp.Body.Close()
-		if resp.StatusCode != 200 {
-			err = ErrInvalidResponse
-		}
+	if err != nil {
+		logentry.Warnf("error at post webhook: %s", err.Error())
 	}
 
 	time := time.Now().UTC()
@@ -142,3 +184,26 @@ func (source *QpWebhook) Post(message *whatsapp.WhatsappMessage) (err error) {
 
 	return
 }
+
+func (source *QpWebhook) postHttp(payloadJson []byte, headers map[string]string) (err error) {
+	req, err := http.NewRequest("POST", source.Url, bytes.NewBuffer(payloadJson))
+	if err != nil {
+		return
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	client := &http.Client{}
+	client.Timeout = ENV.WebhookTimeout()
+	resp, err := client.Do(req)
+	if resp != nil {
+		defer resp.Body.Close()
+		if resp.StatusCode != 200 {
+			err = ErrInvalidResponse
+		}
+	}
+
+	return
+}
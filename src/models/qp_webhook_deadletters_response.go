@@ -0,0 +1,9 @@
+package models
+
+// Dead letters of webhook deliveries, failed after all retries
+type QpWebhookDeadLettersResponse struct {
+	QpResponse
+	Affected    uint                 `json:"affected,omitempty"` // items affected
+	Total       int                  `json:"total,omitempty"`
+	DeadLetters []*QpWebhookDelivery `json:"deadletters,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook delivery model, used for pending queue and dead letters
+type QpWebhookDelivery struct {
+	Id          string          `db:"id" json:"id"`
+	Context     string          `db:"context" json:"-"`
+	Url         string          `db:"url" json:"url"`
+	MessageId   string          `db:"messageid" json:"messageid,omitempty"`
+	Payload     json.RawMessage `db:"payload" json:"payload,omitempty"`
+	Attempts    uint            `db:"attempts" json:"attempts"`
+	NextAttempt time.Time       `db:"nextattempt" json:"nextattempt"`
+	LastError   string          `db:"lasterror" json:"lasterror,omitempty"`
+	Timestamp   time.Time       `db:"timestamp" json:"timestamp"`
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// pseudo event type for read receipts (status updates)
+const WebhookEventReadReceipt = "readreceipt"
+
+// pseudo event type for views of published status (stories)
+const WebhookEventStatusView = "statusview"
+
+/*
+<summary>
+
+	Optional filters for webhook events, evaluated after whatsapp options
+	* types: message type names (text, image, audio, video, document, location, contact, call, system, group, revoke, poll, pollvote, list, buttons, presence) or "readreceipt" for status updates, "statusview" for status (stories) views
+	* chats: glob patterns for chat ids, ex: "*@g.us", "5521*@s.whatsapp.net"
+	* empty allow lists means everything, deny lists has priority
+
+</summary>
+*/
+type QpWebhookFilters struct {
+	AllowTypes []string `json:"allowtypes,omitempty"`
+	DenyTypes  []string `json:"denytypes,omitempty"`
+	AllowChats []string `json:"allowchats,omitempty"`
+	DenyChats  []string `json:"denychats,omitempty"`
+}
+
+// event type used on filters, message type or read receipt pseudo type
+func GetWebhookEventType(message *whatsapp.WhatsappMessage) string {
+	if message.Id == WebhookEventReadReceipt || message.Id == WebhookEventStatusView {
+		return message.Id
+	}
+
+	return message.Type.String()
+}
+
+// first item that is not a message type name or one of the accepted pseudo types
+func FindInvalidEventType(types []string, pseudo ...string) (invalid string, found bool) {
+	for _, item := range types {
+		value := strings.ToLower(strings.TrimSpace(item))
+		if containsFold(pseudo, value) || value == whatsapp.UnknownMessageType.String() {
+			continue
+		}
+
+		if whatsapp.GetMessageTypeFromString(value) == whatsapp.UnknownMessageType {
+			return item, true
+		}
+	}
+
+	return "", false
+}
+
+func (source *QpWebhookFilters) IsEmpty() bool {
+	return source == nil || (len(source.AllowTypes) == 0 && len(source.DenyTypes) == 0 && len(source.AllowChats) == 0 && len(source.DenyChats) == 0)
+}
+
+// checks for invalid type names and glob patterns
+func (source *QpWebhookFilters) Validate() error {
+	if source == nil {
+		return nil
+	}
+
+	types := append(append([]string{}, source.AllowTypes...), source.DenyTypes...)
+	if item, found := FindInvalidEventType(types, WebhookEventReadReceipt, WebhookEventStatusView); found {
+		return fmt.Errorf("invalid webhook filter type: %s", item)
+	}
+
+	for _, item := range append(source.AllowChats, source.DenyChats...) {
+		if _, err := path.Match(item, ""); err != nil {
+			return fmt.Errorf("invalid webhook filter chat pattern: %s", item)
+		}
+	}
+
+	return nil
+}
+
+// indicates that message should be posted
+func (source *QpWebhookFilters) Match(message *whatsapp.WhatsappMessage) bool {
+	if source.IsEmpty() {
+		return true
+	}
+
+	eventType := GetWebhookEventType(message)
+	if containsFold(source.DenyTypes, eventType) {
+		return false
+	}
+
+	if len(source.AllowTypes) > 0 && !containsFold(source.AllowTypes, eventType) {
+		return false
+	}
+
+	chatId := message.Chat.Id
+	if matchAnyGlob(source.DenyChats, chatId) {
+		return false
+	}
+
+	if len(source.AllowChats) > 0 && !matchAnyGlob(source.AllowChats, chatId) {
+		return false
+	}
+
+	return true
+}
+
+func containsFold(items []string, value string) bool {
+	for _, item := range items {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAnyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+//#region DATABASE JSON COLUMN
+
+func (source *QpWebhookFilters) Value() (driver.Value, error) {
+	if source.IsEmpty() {
+		return nil, nil
+	}
+
+	return json.Marshal(source)
+}
+
+func (source *QpWebhookFilters) Scan(value interface{}) error {
+	switch content := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(content) == 0 {
+			return nil
+		}
+		return json.Unmarshal(content, source)
+	case string:
+		if len(content) == 0 {
+			return nil
+		}
+		return json.Unmarshal([]byte(content), source)
+	default:
+		return fmt.Errorf("invalid webhook filters type: %T", value)
+	}
+}
+
+//#endregion
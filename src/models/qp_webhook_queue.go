@@ -0,0 +1,248 @@
+package models
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	library "github.com/nocodeleaks/quepasa/library"
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+)
+
+// maximum deliveries processed at once
+const WebhookQueueBatch uint = 50
+
+// interval to look up for pending deliveries when idle
+const WebhookQueueIdle time.Duration = 30 * time.Second
+
+// Persistent outbound webhook queue, one per server
+type QpWebhookQueue struct {
+	library.LogStruct // logging
+
+	server *QpWhatsappServer
+	db     QpDataWebhookDeliveriesInterface
+
+	signal   chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewQpWebhookQueue(server *QpWhatsappServer, db QpDataWebhookDeliveriesInterface) *QpWebhookQueue {
+	return &QpWebhookQueue{
+		server: server,
+		db:     db,
+		signal: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Saves a delivery for the webhook and wakes up the dispatcher
+func (source *QpWebhookQueue) Enqueue(webhook *QpWebhook, message *whatsapp.WhatsappMessage) (err error) {
+	payload, err := webhook.GetPayload(message)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	element := &QpWebhookDelivery{
+		Id:          uuid.New().String(),
+		Context:     source.server.Token,
+		Url:         webhook.Url,
+		MessageId:   message.Id,
+		Payload:     payload,
+		NextAttempt: now,
+		Timestamp:   now,
+	}
+
+	err = source.db.Enqueue(element)
+	if err != nil {
+		return
+	}
+
+	source.Notify()
+	return
+}
+
+// Wakes up the dispatcher, non blocking
+func (source *QpWebhookQueue) Notify() {
+	select {
+	case source.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Ends dispatcher loop
+func (source *QpWebhookQueue) Stop() {
+	source.stopOnce.Do(func() { close(source.stop) })
+}
+
+// Dispatcher loop, should run on its own routine
+func (source *QpWebhookQueue) Run() {
+	logentry := source.GetLogger()
+	logentry.Debug("starting webhook queue dispatcher")
+
+	for {
+		next, full := source.Dispatch()
+
+		wait := WebhookQueueIdle
+		if full {
+			wait = 0
+		} else if !next.IsZero() {
+			if until := time.Until(next); until < wait {
+				wait = until
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-source.stop:
+			timer.Stop()
+			logentry.Debug("stopping webhook queue dispatcher")
+			return
+		case <-source.signal:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// Posts due deliveries, returns the earliest next attempt scheduled and if batch was full
+func (source *QpWebhookQueue) Dispatch() (next time.Time, full bool) {
+	logentry := source.GetLogger()
+
+	elements, err := source.db.FindPending(source.server.Token, time.Now().UTC(), WebhookQueueBatch)
+	if err != nil {
+		logentry.Errorf("error on getting pending webhook deliveries: %s", err.Error())
+		return
+	}
+
+	full = uint(len(elements)) >= WebhookQueueBatch
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, element := range elements {
+		wg.Add(1)
+		go func(element *QpWebhookDelivery) {
+			defer wg.Done()
+
+			scheduled := source.Deliver(element)
+			if !scheduled.IsZero() {
+				mutex.Lock()
+				if next.IsZero() || scheduled.Before(next) {
+					next = scheduled
+				}
+				mutex.Unlock()
+			}
+		}(element)
+	}
+
+	wg.Wait()
+	return
+}
+
+// Posts a single delivery, returns next attempt if it was rescheduled
+func (source *QpWebhookQueue) Deliver(element *QpWebhookDelivery) (next time.Time) {
+	logentry := source.GetLogger()
+	loglevel := logentry.Level
+	logentry = logentry.WithField(LogFields.MessageId, element.MessageId)
+	logentry = logentry.WithField(LogFields.Url, element.Url)
+	logentry.Level = loglevel
+
+	var webhook *QpWebhook
+	if whook := source.server.GetWebHook(element.Url); whook != nil {
+		webhook = whook.QpWebhook
+	}
+
+	// webhook removed since enqueue
+	if webhook == nil {
+		logentry.Infof("discarding delivery for a removed webhook")
+		err := source.db.Remove(element.Id)
+		if err != nil {
+			logentry.Errorf("error on removing webhook delivery: %s", err.Error())
+		}
+		return
+	}
+
+	err := webhook.PostPayload(element.MessageId, element.Payload)
+	if err == nil {
+		err = source.db.Remove(element.Id)
+		if err != nil {
+			logentry.Errorf("error on removing delivered webhook: %s", err.Error())
+		}
+		return
+	}
+
+	element.Attempts++
+	element.LastError = err.Error()
+
+	retries := ENV.WebhookRetries()
+	if uint64(element.Attempts) > retries {
+		logentry.Warnf("webhook delivery failed after %v attempt(s), moving to dead letters: %s", element.Attempts, element.LastError)
+		err = source.db.Kill(element)
+		if err != nil {
+			logentry.Errorf("error on moving webhook delivery to dead letters: %s", err.Error())
+		}
+		return
+	}
+
+	element.NextAttempt = time.Now().UTC().Add(GetWebhookBackoff(element.Attempts))
+	logentry.Infof("webhook delivery failed, attempt: %v, retrying at: %s", element.Attempts, element.NextAttempt)
+
+	err = source.db.Update(element)
+	if err != nil {
+		logentry.Errorf("error on updating webhook delivery: %s", err.Error())
+	}
+
+	return element.NextAttempt
+}
+
+// Interval before the next webhook delivery attempt
+func GetWebhookBackoff(attempts uint) time.Duration {
+	return GetExponentialBackoff(attempts, ENV.WebhookBackoff(), ENV.WebhookBackoffMax())
+}
+
+// Exponential backoff with jitter, between half and full interval
+func GetExponentialBackoff(attempts uint, interval time.Duration, maximum time.Duration) time.Duration {
+	for i := uint(1); i < attempts && interval < maximum; i++ {
+		interval *= 2
+	}
+
+	if interval > maximum {
+		interval = maximum
+	}
+
+	half := interval / 2
+	if half <= 0 {
+		return interval
+	}
+
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+//#region DEAD LETTERS
+
+// Lists dead letters, optional filter by webhook url
+func (source *QpWebhookQueue) DeadLetters(url string) ([]*QpWebhookDelivery, error) {
+	return source.db.FindDead(source.server.Token, url)
+}
+
+// Moves dead letters back to pending queue and wakes up the dispatcher, optional filter by webhook url and id
+func (source *QpWebhookQueue) Replay(url string, id string) (affected uint, err error) {
+	affected, err = source.db.Revive(source.server.Token, url, id)
+	if err != nil {
+		return
+	}
+
+	if affected > 0 {
+		source.Notify()
+	}
+	return
+}
+
+// Removes dead letters, optional filter by webhook url and id
+func (source *QpWebhookQueue) Purge(url string, id string) (uint, error) {
+	return source.db.Purge(source.server.Token, url, id)
+}
+
+//#endregion
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// WebSocket command actions
+const (
+	// sends a message, default when action is empty
+	WebSocketActionSend = "send"
+
+	// replaces the connection filters
+	WebSocketActionSubscribe = "subscribe"
+
+	// removes the connection filters, receiving all events again
+	WebSocketActionUnsubscribe = "unsubscribe"
+
+	WebSocketActionPing = "ping"
+)
+
+/*
+<summary>
+
+	Command received from a websocket client
+	Send commands uses the same shape as send endpoint body, plus action and requestid
+	* ex: {"action": "send", "requestid": "1", "chatid": "5511999999999", "text": "hello"}
+	* ex: {"action": "subscribe", "requestid": "2", "filters": {"allowchats": ["*@g.us"], "allowtypes": ["text"]}}
+
+</summary>
+*/
+type QpWebSocketCommand struct {
+	Action string `json:"action,omitempty"`
+
+	// client correlation, echoed on the response
+	RequestId string `json:"requestid,omitempty"`
+
+	// subscribe, by chat id and message type, same as webhook filters
+	Filters *QpWebhookFilters `json:"filters,omitempty"`
+
+	QpSendAnyRequest
+}
+
+func (source *QpWebSocketCommand) GetAction() string {
+	action := strings.ToLower(strings.TrimSpace(source.Action))
+	if len(action) == 0 {
+		return WebSocketActionSend
+	}
+	return action
+}
+
+// Response of a websocket command, differs from pushed events by "action" field
+type QpWebSocketResponse struct {
+	Action    string `json:"action"`
+	RequestId string `json:"requestid,omitempty"`
+
+	// same response of the equivalent http endpoint
+	Response json.RawMessage `json:"response"`
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"database/sql"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/nocodeleaks/quepasa/library"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
@@ -14,6 +18,9 @@ type QPWhatsappHandlers struct {
 
 	server *QpWhatsappServer
 
+	// durable message store, optional
+	store QpDataMessagesInterface
+
 	syncRegister *sync.Mutex
 
 	// Appended events handler
@@ -55,13 +62,23 @@ func (source *QPWhatsappHandlers) Message(msg *whatsapp.WhatsappMessage, from st
 		return
 	}
 
+	// should skip newsletters ?
+	if !whatsapp.Options.HandleNewsletters() && msg.FromNewsletter() {
+		return
+	}
+
+	// incoming call rules, may reject before dispatching
+	if msg.Call != nil && msg.Call.State == whatsapp.WhatsappCallOffer && !msg.FromMe && source.server != nil {
+		source.server.ApplyCallRules(msg)
+	}
+
 	// messages sended with chat title
 	if len(msg.Chat.Title) == 0 {
 		msg.Chat.Title = source.server.GetChatTitle(msg.Chat.Id)
 	}
 
 	if len(msg.InReply) > 0 {
-		cached, err := source.QpWhatsappMessages.GetById(msg.InReply)
+		cached, err := source.GetById(msg.InReply)
 		if err == nil {
 			maxlength := ENV.SynopsisLength() - 4
 			if uint64(len(cached.Text)) > maxlength {
@@ -92,10 +109,19 @@ func (source *QPWhatsappHandlers) Receipt(msg *whatsapp.WhatsappMessage) {
 	// should implement a better method for that !!!!
 	// should implement a better method for that !!!!
 
+	// streaming to event subscribers
+	source.PublishEvent(&QpEvent{Type: EventTypeReceipt, Data: msg})
+
 	// triggering external publishers
 	source.Trigger(msg)
 }
 
+// does not cache msg, only webhook dispatch
+func (source *QPWhatsappHandlers) Presence(msg *whatsapp.WhatsappMessage) {
+	source.PublishEvent(&QpEvent{Type: EventTypePresence, Data: msg})
+	source.Trigger(msg)
+}
+
 //endregion
 
 /*
@@ -124,6 +150,8 @@ func (source *QPWhatsappHandlers) LoggedOut(reason string) {
 
 		// marking unverified and wait for more analyses
 		source.server.MarkVerified(false)
+
+		EventHub.PublishState(source.server.Token, whatsapp.UnVerified, reason)
 	}
 }
 
@@ -146,6 +174,8 @@ func (source *QPWhatsappHandlers) OnConnected() {
 			logger := source.server.GetLogger()
 			logger.Errorf("error on mark verified after connected: %s", err.Error())
 		}
+
+		EventHub.PublishState(source.server.Token, source.server.GetStatus(), "connected")
 	}
 }
 
@@ -158,7 +188,9 @@ func (source *QPWhatsappHandlers) OnConnected() {
 </summary>
 */
 func (source *QPWhatsappHandlers) OnDisconnected() {
-
+	if source.server != nil {
+		EventHub.PublishState(source.server.Token, source.server.GetStatus(), "disconnected")
+	}
 }
 
 //#endregion
@@ -177,12 +209,229 @@ func (source *QPWhatsappHandlers) appendMsgToCache(msg *whatsapp.WhatsappMessage
 		length := ENV.CacheLength()
 		source.QpWhatsappMessages.CleanUp(length)
 
+		// saving on durable store, if enabled
+		source.appendMsgToStore(msg)
+
+		// streaming to event subscribers, resumable by id
+		if source.server != nil {
+			EventHub.PublishMessage(source.server.Token, msg)
+		}
+
 		source.Trigger(msg)
 	}
 }
 
+// writes through to durable message store, if enabled
+func (source *QPWhatsappHandlers) appendMsgToStore(msg *whatsapp.WhatsappMessage) {
+	if source.store == nil || source.server == nil {
+		return
+	}
+
+	element, err := NewQpServerMessage(source.server.Token, msg)
+	if err == nil {
+		err = source.store.Add(element)
+	}
+
+	if err != nil {
+		logentry := source.GetLogger()
+		logentry.Warnf("error on saving message to store, id: %s, cause: %s", msg.Id, err.Error())
+	}
+}
+
+// Gets a message from cache, or from durable store if enabled
 func (source *QPWhatsappHandlers) GetById(id string) (*whatsapp.WhatsappMessage, error) {
-	return source.QpWhatsappMessages.GetById(id)
+	msg, err := source.QpWhatsappMessages.GetById(id)
+	if err == nil || source.store == nil || source.server == nil {
+		return msg, err
+	}
+
+	element, storeErr := source.store.Find(source.server.Token, id)
+	if storeErr != nil {
+		return msg, err
+	}
+
+	return element.ToWhatsappMessage()
+}
+
+// Returns cached messages and stored ones (if enabled), based on a time filter
+func (source *QPWhatsappHandlers) GetByTime(timestamp time.Time) (messages []*whatsapp.WhatsappMessage) {
+	messages = source.QpWhatsappMessages.GetByTime(timestamp)
+	if source.store == nil || source.server == nil {
+		return
+	}
+
+	elements, err := source.store.FindByTime(source.server.Token, timestamp)
+	if err != nil {
+		logentry := source.GetLogger()
+		logentry.Warnf("error on getting messages from store: %s", err.Error())
+		return
+	}
+
+	return source.mergeStored(messages, elements)
+}
+
+/*
+<summary>
+
+	Searches stored messages with full text index, if durable store is enabled
+	Otherwise scans cached messages, results are ordered by newest first
+
+</summary>
+*/
+func (source *QPWhatsappHandlers) Search(filter *QpMessageSearchFilter) (messages []*whatsapp.WhatsappMessage, total uint, err error) {
+	if source.store != nil && source.server != nil {
+		total, err = source.store.SearchCount(source.server.Token, filter)
+		if err != nil {
+			return
+		}
+
+		elements, err := source.store.Search(source.server.Token, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		messages = source.mergeStored(messages, elements)
+		return messages, total, nil
+	}
+
+	for _, item := range source.QpWhatsappMessages.GetSlice() {
+		if filter.Match(item) {
+			messages = append(messages, item)
+		}
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp.After(messages[j].Timestamp)
+	})
+
+	total = uint(len(messages))
+	if filter.Offset >= total {
+		return nil, total, nil
+	}
+
+	messages = messages[filter.Offset:]
+	if filter.Limit > 0 && uint(len(messages)) > filter.Limit {
+		messages = messages[:filter.Limit]
+	}
+
+	return
+}
+
+// Returns cached messages and stored ones (if enabled), that starts with an id prefix
+func (source *QPWhatsappHandlers) GetByPrefix(id string) (messages []*whatsapp.WhatsappMessage) {
+	messages = source.QpWhatsappMessages.GetByPrefix(id)
+	if source.store == nil || source.server == nil {
+		return
+	}
+
+	elements, err := source.store.FindByPrefix(source.server.Token, id)
+	if err != nil {
+		logentry := source.GetLogger()
+		logentry.Warnf("error on getting messages from store: %s", err.Error())
+		return
+	}
+
+	return source.mergeStored(messages, elements)
+}
+
+// Indicates that a chat has messages before this one, on cache or durable store (if enabled)
+func (source *QPWhatsappHandlers) HasPrevious(message *whatsapp.WhatsappMessage) bool {
+	for _, item := range source.GetSlice() {
+		if item.Chat.Id == message.Chat.Id && item.Id != message.Id && !item.Timestamp.After(message.Timestamp) {
+			return true
+		}
+	}
+
+	if source.store == nil || source.server == nil {
+		return false
+	}
+
+	count, err := source.store.CountPrevious(source.server.Token, message.Chat.Id, message.Id, message.Timestamp)
+	if err != nil {
+		// assuming previous contact, avoiding unwanted first contact actions
+		logentry := source.GetLogger()
+		logentry.Warnf("error on counting stored messages, chat: %s, cause: %s", message.Chat.Id, err.Error())
+		return true
+	}
+
+	return count > 0
+}
+
+// appends stored messages that are not present on cached ones
+func (source *QPWhatsappHandlers) mergeStored(messages []*whatsapp.WhatsappMessage, elements []*QpServerMessage) []*whatsapp.WhatsappMessage {
+	cached := make(map[string]bool)
+	for _, item := range messages {
+		cached[strings.ToUpper(item.Id)] = true
+	}
+
+	for _, element := range elements {
+		if cached[element.Id] {
+			continue
+		}
+
+		msg, err := element.ToWhatsappMessage()
+		if err != nil {
+			logentry := source.GetLogger()
+			logentry.Warnf("error on restoring stored message, id: %s, cause: %s", element.Id, err.Error())
+			continue
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages
+}
+
+// Updates message status on cache and durable store (if enabled)
+// Messages not cached anymore are compared with the stored status
+func (source *QPWhatsappHandlers) MessageStatusUpdate(id string, status whatsapp.WhatsappMessageStatus) bool {
+	updated := source.QpWhatsappMessages.MessageStatusUpdate(id, status)
+	if source.store != nil && source.server != nil {
+		if _, err := source.QpWhatsappMessages.GetById(id); err != nil {
+			if stored, found := source.storeStatusUpdate(id, status); found {
+				updated = stored
+			}
+		} else if updated {
+			err = source.store.UpdateStatus(source.server.Token, id, string(status))
+			if err != nil {
+				logentry := source.GetLogger()
+				logentry.Warnf("error on updating message status on store, id: %s, cause: %s", id, err.Error())
+			}
+		}
+	}
+
+	if updated && source.server != nil {
+		EventHub.PublishStatus(source.server.Token, id, status)
+	}
+
+	return updated
+}
+
+// updates stored message status if newer, for messages not cached, found indicates that it is stored
+func (source *QPWhatsappHandlers) storeStatusUpdate(id string, status whatsapp.WhatsappMessageStatus) (updated bool, found bool) {
+	logentry := source.GetLogger()
+
+	element, err := source.store.Find(source.server.Token, id)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logentry.Warnf("error on getting message from store, id: %s, cause: %s", id, err.Error())
+		}
+		return
+	}
+
+	found = true
+	if whatsapp.WhatsappMessageStatus(element.Status).Uint32() >= status.Uint32() {
+		return
+	}
+
+	err = source.store.UpdateStatus(source.server.Token, id, string(status))
+	if err != nil {
+		logentry.Warnf("error on updating message status on store, id: %s, cause: %s", id, err.Error())
+		return
+	}
+
+	updated = true
+	return
 }
 
 // endregion
@@ -201,6 +450,13 @@ func (source *QPWhatsappHandlers) Trigger(payload *whatsapp.WhatsappMessage) {
 	}
 }
 
+// sends an event to stream subscribers of this server
+func (source *QPWhatsappHandlers) PublishEvent(event *QpEvent) {
+	if source != nil && source.server != nil {
+		EventHub.Publish(source.server.Token, event)
+	}
+}
+
 // Register an event handler that triggers on a new message received on cache
 func (handler *QPWhatsappHandlers) Register(evt QpWebhookHandlerInterface) {
 	handler.syncRegister.Lock() // await for avoid simultaneous calls
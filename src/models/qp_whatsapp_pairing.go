@@ -59,6 +59,22 @@ func (source *QpWhatsappPairing) OnPaired(wid string) {
 		return
 	}
 
+	audit := &QpAuditEntry{
+		ActorType: AuditActorToken,
+		Actor:     source.Token,
+		Action:    AuditActionServerCreate,
+		Target:    source.Token,
+		Resource:  source.Wid,
+		Details:   "paired whatsapp section",
+	}
+
+	if len(source.Username) > 0 {
+		audit.ActorType = AuditActorUser
+		audit.Actor = source.Username
+	}
+
+	Audit(audit)
+
 	go server.EnsureReady()
 }
 
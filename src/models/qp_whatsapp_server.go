@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +13,8 @@ import (
 	"github.com/google/uuid"
 	library "github.com/nocodeleaks/quepasa/library"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
 )
 
 type QpWhatsappServer struct {
@@ -30,8 +33,14 @@ type QpWhatsappServer struct {
 
 	StartTime time.Time `json:"starttime,omitempty"`
 
-	Handler *QPWhatsappHandlers `json:"-"`
-	WebHook *QPWebhookHandler   `json:"-"`
+	Handler      *QPWhatsappHandlers `json:"-"`
+	WebHook      *QPWebhookHandler   `json:"-"`
+	WebhookQueue *QpWebhookQueue     `json:"-"`
+	SendQueue    *QpSendQueue        `json:"-"`
+	AutoRules    *QpAutoRules        `json:"-"`
+
+	ChatwootHandler *QpChatwootHandler `json:"-"`
+	syncChatwoot    *sync.RWMutex      `json:"-"` // settings are replaced while handlers are reading
 
 	// Stop request token
 	StopRequested bool                   `json:"-"`
@@ -106,6 +115,20 @@ func (server *QpWhatsappServer) HandlerEnsure() {
 	}
 }
 
+// Ensure durable message store for default handler, only if enabled
+func (server *QpWhatsappServer) MessageStoreEnsure(store QpDataMessagesInterface) {
+	if server == nil || server.Handler == nil {
+		return // invalid state
+	}
+
+	if ENV.MessageStore() && server.Handler.store == nil {
+		logentry := server.GetLogger()
+		logentry.Debug("ensuring durable message store for server")
+
+		server.Handler.store = store
+	}
+}
+
 func (server *QpWhatsappServer) HasSignalRActiveConnections() bool {
 	if server == nil {
 		return false // invalid state
@@ -203,6 +226,62 @@ func (source *QpWhatsappServer) Revoke(id string) (err error) {
 	return source.connection.Revoke(msg)
 }
 
+// Edits the text of a cached message sent by this server, dispatching the edited message to webhooks
+func (source *QpWhatsappServer) Edit(id string, text string) (edited *whatsapp.WhatsappMessage, err error) {
+	msg, err := source.Handler.GetById(id)
+	if err != nil {
+		return
+	}
+
+	if !msg.FromMe {
+		err = fmt.Errorf("only own messages can be edited")
+		return
+	}
+
+	if msg.Type != whatsapp.TextMessageType {
+		err = fmt.Errorf("only text messages can be edited, type: %s", msg.Type)
+		return
+	}
+
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return
+	}
+
+	source.GetLogger().Infof("editing msg %s", id)
+	err = conn.Edit(msg, text)
+	if err != nil {
+		return
+	}
+
+	// updating cache and triggering webhooks with the edited copy
+	element := *msg
+	element.Text = text
+	element.Edited = true
+	element.FromInternal = true
+	element.Content = &waE2E.Message{Conversation: proto.String(text)}
+
+	edited = &element
+	source.Handler.Message(edited, "server edit")
+	return
+}
+
+// Sends an emoji reaction to a cached message, empty reaction removes it
+func (source *QpWhatsappServer) React(id string, reaction string) (err error) {
+	msg, err := source.Handler.GetById(id)
+	if err != nil {
+		return
+	}
+
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return
+	}
+
+	source.GetLogger().Infof("reacting to msg %s with: %s", id, reaction)
+	return conn.React(msg, reaction)
+}
+
 //endregion
 
 //#region WEBHOOKS
@@ -244,6 +323,92 @@ func (server *QpWhatsappServer) WebHookEnsure() {
 	}
 }
 
+// Ensure persistent webhook delivery queue and its dispatcher
+func (server *QpWhatsappServer) WebhookQueueEnsure(db QpDataWebhookDeliveriesInterface) {
+	if server.WebhookQueue == nil {
+		queue := NewQpWebhookQueue(server, db)
+
+		logentry := server.GetLogger()
+		logentry.Debug("ensuring webhook queue for server")
+
+		// logging
+		queue.LogEntry = logentry
+
+		// updating
+		server.WebhookQueue = queue
+		go queue.Run()
+	}
+}
+
+// Ensure persistent send queue and its dispatcher
+func (server *QpWhatsappServer) SendQueueEnsure(db QpDataSendQueueInterface) {
+	if server.SendQueue == nil {
+		queue := NewQpSendQueue(server, db)
+
+		logentry := server.GetLogger()
+		logentry.Debug("ensuring send queue for server")
+
+		// logging
+		queue.LogEntry = logentry
+
+		// updating
+		server.SendQueue = queue
+		go queue.Run()
+	}
+}
+
+// Ensure auto reply and keyword rules engine, loading rules from database
+func (server *QpWhatsappServer) AutoRulesEnsure(db QpDataAutoRulesInterface) {
+	if server.AutoRules == nil {
+		rules := NewQpAutoRules(server, db)
+
+		logentry := server.GetLogger()
+		logentry.Debug("ensuring auto rules for server")
+
+		// logging
+		rules.LogEntry = logentry
+
+		err := rules.Load()
+		if err != nil {
+			logentry.Errorf("error on loading auto rules: %s", err.Error())
+		}
+
+		// updating
+		server.AutoRules = rules
+	}
+}
+
+// Ensure chatwoot channel sink, inactive until configured
+func (server *QpWhatsappServer) ChatwootEnsure() {
+	if server.ChatwootHandler == nil {
+		handler := NewQpChatwootHandler(server)
+
+		logentry := server.GetLogger()
+		logentry.Debug("ensuring chatwoot handler for server")
+
+		// logging
+		handler.LogEntry = logentry
+
+		// updating
+		server.ChatwootHandler = handler
+	}
+}
+
+// Registers webhook dispatcher, auto rules engine and chatwoot sink as event handlers, if not already
+func (server *QpWhatsappServer) EnsureEventHandlers() {
+	if !server.Handler.IsRegistered(server.WebHook) {
+		server.Handler.Register(server.WebHook)
+	}
+
+	if server.AutoRules != nil && !server.Handler.IsRegistered(server.AutoRules) {
+		server.Handler.Register(server.AutoRules)
+	}
+
+	if server.ChatwootHandler != nil && !server.Handler.IsRegistered(server.ChatwootHandler) {
+		server.Handler.Register(server.ChatwootHandler)
+	}
+}
+
 //#endregion
 
 func (server *QpWhatsappServer) GetMessages(timestamp time.Time) (messages []whatsapp.WhatsappMessage) {
@@ -261,6 +426,32 @@ func (server *QpWhatsappServer) GetMessages(timestamp time.Time) (messages []wha
 	return
 }
 
+/*
+<summary>
+
+	Messages received after an specific one, ordered by time, used to resume event streams
+	Messages on the same second of the reference are included again (at least once delivery)
+
+</summary>
+*/
+func (server *QpWhatsappServer) GetMessagesAfter(id string) (messages []*whatsapp.WhatsappMessage, err error) {
+	reference, err := server.Handler.GetById(id)
+	if err != nil {
+		return
+	}
+
+	for _, item := range server.Handler.GetByTime(reference.Timestamp.Add(-1 * time.Second)) {
+		if item.Id != reference.Id {
+			messages = append(messages, item)
+		}
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp.Before(messages[j].Timestamp)
+	})
+	return
+}
+
 // Roda de forma assíncrona, não interessa o resultado ao chamador
 // Inicia o processo de tentativas de conexão de um servidor individual
 func (source *QpWhatsappServer) Initialize() {
@@ -292,11 +483,9 @@ func (source *QpWhatsappServer) UpdateConnection(connection whatsapp.IWhatsappCo
 
 	source.connection.UpdateHandler(source.Handler)
 
-	// Registrando webhook
-	webhookDispatcher := &QPWebhookHandler{server: source}
-	if !source.Handler.IsAttached() {
-		source.Handler.Register(webhookDispatcher)
-	}
+	// Registrando webhook, regras automaticas e chatwoot
+	source.WebHookEnsure()
+	source.EnsureEventHandlers()
 }
 
 func (source *QpWhatsappServer) EnsureUnderlying() (err error) {
@@ -361,11 +550,8 @@ func (source *QpWhatsappServer) Start() (err error) {
 	// reset stop requested token
 	source.StopRequested = false
 
-	if !source.Handler.IsAttached() {
-
-		// Registrando webhook
-		source.Handler.Register(source.WebHook)
-	}
+	// Registrando webhook, regras automaticas e chatwoot
+	source.EnsureEventHandlers()
 
 	// Atualizando manipuladores de eventos
 	source.connection.UpdateHandler(source.Handler)
@@ -408,13 +594,13 @@ func (source *QpWhatsappServer) EnsureReady() (err error) {
 
 	if !source.Handler.IsAttached() {
 		logger.Info("attaching handlers")
-
-		// Registrando webhook
-		source.Handler.Register(source.WebHook)
 	} else {
 		logger.Debug("handlers already attached")
 	}
 
+	// Registrando webhook, regras automaticas e chatwoot
+	source.EnsureEventHandlers()
+
 	// Atualizando manipuladores de eventos
 	source.connection.UpdateHandler(source.Handler)
 
@@ -465,6 +651,8 @@ func (source *QpWhatsappServer) Stop(cause string) (err error) {
 		if source.Handler != nil {
 			source.Handler.Clear()
 		}
+
+		EventHub.PublishState(source.Token, source.GetStatus(), "stop: "+cause)
 	}
 
 	return
@@ -686,6 +874,30 @@ func (source *QpWhatsappServer) ToggleDevel() (handle bool, err error) {
 
 //endregion
 
+// unlinks the whatsapp device, keeping server settings for a new pairing with the same token
+func (server *QpWhatsappServer) Logout() (err error) {
+	err = server.Stop("logout")
+	if err != nil {
+		return
+	}
+
+	if server.connection != nil {
+		err = server.connection.Delete()
+		if err != nil {
+			return
+		}
+
+		server.connection = nil
+	}
+
+	err = server.MarkVerified(false)
+	if err == nil {
+		EventHub.PublishState(server.Token, whatsapp.UnVerified, "logout")
+	}
+
+	return
+}
+
 // delete this whatsapp server and underlaying connection
 func (server *QpWhatsappServer) Delete() (err error) {
 	if server.connection != nil {
@@ -703,6 +915,27 @@ func (server *QpWhatsappServer) Delete() (err error) {
 //endregion
 //#region SEND
 
+// Indicates that sends should go through send queue, enabled or a schedule was informed
+func (source *QpWhatsappServer) ShouldEnqueue(sendat *time.Time) bool {
+	return source.SendQueue != nil && (sendat != nil || ENV.SendQueue())
+}
+
+// Enqueues the message if should, otherwise sends it now
+func (source *QpWhatsappServer) SendMessageOrEnqueue(msg *whatsapp.WhatsappMessage, sendat *time.Time) (response whatsapp.IWhatsappSendResponse, queued bool, err error) {
+	if source.ShouldEnqueue(sendat) {
+		var schedule time.Time
+		if sendat != nil {
+			schedule = *sendat
+		}
+
+		_, err = source.SendQueue.Enqueue(msg, schedule)
+		return msg, true, err
+	}
+
+	response, err = source.SendMessage(msg)
+	return response, false, err
+}
+
 // Default send message method
 func (source *QpWhatsappServer) SendMessage(msg *whatsapp.WhatsappMessage) (response whatsapp.IWhatsappSendResponse, err error) {
 	logger := source.GetLogger()
@@ -799,6 +1032,253 @@ func (source *QpWhatsappServer) GetInvite(groupId string) (link string, err erro
 	return conn.GetInvite(groupId)
 }
 
+//#endregion
+//#region GROUPS
+
+// Group management methods of a valid connection
+func (source *QpWhatsappServer) GetGroupManager() (whatsapp.IWhatsappConnectionGroups, error) {
+	return source.GetValidConnection()
+}
+
+//#endregion
+//#region CALLS
+
+// Applies the first matching call rule on an incoming call offer, rejecting and replying
+func (source *QpWhatsappServer) ApplyCallRules(msg *whatsapp.WhatsappMessage) {
+	rule := source.CallRules.Match(msg, time.Now())
+	if rule == nil {
+		return
+	}
+
+	logentry := source.GetLogger()
+	logentry = logentry.WithField(LogFields.MessageId, msg.Id)
+	logentry = logentry.WithField(LogFields.ChatId, msg.Chat.Id)
+
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		logentry.Errorf("error on applying call rule: %s", err.Error())
+		return
+	}
+
+	if rule.Action == CallRuleActionReject && !msg.Call.Rejected {
+		err = conn.RejectCall(msg.Chat.Id, msg.Call.Id)
+		if err != nil {
+			logentry.Errorf("error on rejecting call by rule: %s", err.Error())
+		} else {
+			logentry.Infof("call rejected by rule")
+			msg.Call.Rejected = true
+		}
+	}
+
+	if len(rule.Reply) > 0 {
+		reply := &whatsapp.WhatsappMessage{
+			Chat:         whatsapp.WhatsappChat{Id: msg.Chat.Id},
+			Type:         whatsapp.TextMessageType,
+			Text:         rule.Reply,
+			FromMe:       true,
+			FromInternal: true,
+		}
+
+		go func() {
+			_, err := source.SendMessage(reply)
+			if err != nil {
+				logentry.Errorf("error on replying call by rule: %s", err.Error())
+			}
+		}()
+	}
+}
+
+// Validates and saves incoming call rules, empty rules removes all
+func (source *QpWhatsappServer) SetCallRules(rules *QpCallRules) error {
+	err := rules.Validate()
+	if err != nil {
+		return err
+	}
+
+	if rules.IsEmpty() {
+		rules = nil
+	}
+
+	source.CallRules = rules
+	return source.Save("call rules updated")
+}
+
+//#endregion
+//#region CHATWOOT
+
+// Current chatwoot channel settings, replaced entirely on updates, do not modify
+func (source *QpWhatsappServer) GetChatwoot() *QpChatwootConfig {
+	source.syncChatwoot.RLock()
+	defer source.syncChatwoot.RUnlock()
+	return source.Chatwoot
+}
+
+// Updates chatwoot channel settings, nil removes the integration
+// An empty access token keeps the current one, webhook secret is kept or generated
+func (source *QpWhatsappServer) SetChatwoot(config *QpChatwootConfig) error {
+	if config != nil {
+		config.Secret = ""
+		if current := source.GetChatwoot(); current != nil {
+			if len(config.Token) == 0 {
+				config.Token = current.Token
+			}
+			config.Secret = current.Secret
+		}
+	}
+
+	err := config.Validate()
+	if err != nil {
+		return err
+	}
+
+	if config != nil && len(config.Secret) == 0 {
+		config.Secret, err = library.GenerateWebhookSecret()
+		if err != nil {
+			return err
+		}
+	}
+
+	source.syncChatwoot.Lock()
+	source.Chatwoot = config
+	source.syncChatwoot.Unlock()
+
+	if source.ChatwootHandler != nil {
+		source.ChatwootHandler.Reset()
+	}
+
+	return source.Save("chatwoot updated")
+}
+
+//#endregion
+//#region NEWSLETTERS
+
+// Newsletter (channels) methods of a valid connection
+func (source *QpWhatsappServer) GetNewsletterManager() (whatsapp.IWhatsappConnectionNewsletters, error) {
+	return source.GetValidConnection()
+}
+
+//#endregion
+//#region PRESENCE
+
+// Sets global presence (available or unavailable) for this server, until restart
+func (source *QpWhatsappServer) SetPresence(available bool) error {
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return err
+	}
+
+	source.GetLogger().Infof("setting presence, available: %v", available)
+	return conn.SetPresence(available)
+}
+
+// Sends chat presence (typing, recording, paused) to a chat
+func (source *QpWhatsappServer) SendChatPresence(chatId string, presence whatsapp.WhatsappChatPresenceType) error {
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return err
+	}
+
+	return conn.SendChatPresence(chatId, presence)
+}
+
+// Subscribes to a contact presence, updates are emitted to webhooks as presence events
+func (source *QpWhatsappServer) SubscribePresence(wid string) error {
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return err
+	}
+
+	return conn.SubscribePresence(wid)
+}
+
+//#endregion
+//#region READ
+
+// Marks cached messages as read, by ids or every received message of a chat up to now, returns marked ids
+func (source *QpWhatsappServer) MarkRead(ids []string, chatId string) (read []string, err error) {
+	var messages []*whatsapp.WhatsappMessage
+	for _, id := range ids {
+		msg, err := source.Handler.GetById(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if msg.FromMe {
+			return nil, fmt.Errorf("own messages can not be marked as read, id: %s", id)
+		}
+
+		messages = append(messages, msg)
+	}
+
+	if len(chatId) > 0 {
+		informed := make(map[string]bool)
+		for _, msg := range messages {
+			informed[msg.Id] = true
+		}
+
+		now := time.Now()
+		for _, msg := range source.Handler.GetSlice() {
+			if msg.Chat.Id != chatId || msg.FromMe || msg.Timestamp.After(now) || informed[msg.Id] {
+				continue
+			}
+
+			if msg.Type == whatsapp.SystemMessageType || msg.Type == whatsapp.PresenceMessageType {
+				continue
+			}
+
+			messages = append(messages, msg)
+		}
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return
+	}
+
+	err = conn.MarkRead(messages...)
+	if err != nil {
+		return
+	}
+
+	for _, msg := range messages {
+		read = append(read, msg.Id)
+	}
+
+	source.GetLogger().Infof("marked as read %v message(s)", len(read))
+	return
+}
+
+//#endregion
+//#region STATUS
+
+// Received status (stories) on cache, ordered by timestamp, requires broadcasts handling
+func (source *QpWhatsappServer) GetStatuses() (messages []*whatsapp.WhatsappMessage) {
+	for _, msg := range source.Handler.GetSlice() {
+		if msg.Chat.Id == whatsapp.WhatsappStatusChatId {
+			messages = append(messages, msg)
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].Timestamp.Before(messages[j].Timestamp)
+	})
+	return
+}
+
+// Audience of published status, from whatsapp privacy settings
+func (source *QpWhatsappServer) GetStatusPrivacy() (*whatsapp.WhatsappStatusPrivacy, error) {
+	conn, err := source.GetValidConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	return conn.GetStatusPrivacy()
+}
+
 //#endregion
 //#region GET ALL CONTACTS
 
@@ -28,7 +28,7 @@ func PostToWebHookFromServer(server *QpWhatsappServer, message *whatsapp.Whatsap
 		logentry = logentry.WithField(LogFields.MessageId, message.Id)
 		logentry.Level = loglevel
 
-		if message.Id == "readreceipt" && element.IsSetReadReceipts() && !element.ReadReceipts.Boolean() {
+		if (message.Id == WebhookEventReadReceipt || message.Id == WebhookEventStatusView) && element.IsSetReadReceipts() && !element.ReadReceipts.Boolean() {
 			logentry.Debugf("ignoring read receipt message: %s", message.Text)
 			continue
 		}
@@ -48,7 +48,29 @@ func PostToWebHookFromServer(server *QpWhatsappServer, message *whatsapp.Whatsap
 			continue
 		}
 
+		if message.Type == whatsapp.PresenceMessageType && !element.HandlePresences() {
+			logentry.Debug("ignoring presence message")
+			continue
+		}
+
+		if !element.Filters.Match(message) {
+			logentry.Debugf("ignoring by webhook filters, type: %s, chat: %s", GetWebhookEventType(message), message.Chat.Id)
+			continue
+		}
+
 		if !message.FromInternal || (element.ForwardInternal && (len(element.TrackId) == 0 || element.TrackId != message.TrackId)) {
+
+			// persistent queue, retries with backoff until dead letters
+			// presence is transient, not worth retrying
+			if server.WebhookQueue != nil && message.Type != whatsapp.PresenceMessageType {
+				elerr := server.WebhookQueue.Enqueue(element, message)
+				if elerr == nil {
+					continue
+				}
+
+				logentry.Errorf("error on enqueue webhook, posting directly: %s", elerr.Error())
+			}
+
 			elerr := element.Post(message)
 			if elerr != nil {
 				logentry.Errorf("error on post webhook: %s", elerr.Error())
@@ -106,6 +128,22 @@ func GetServerFromToken(token string) (server *QpWhatsappServer, err error) {
 	return
 }
 
+// Find the server with chatwoot integration using this inbox webhook secret
+func GetServerFromChatwootSecret(secret string) (server *QpWhatsappServer, err error) {
+	for _, item := range WhatsappService.Servers {
+		if item != nil && item.GetChatwoot().MatchSecret(secret) {
+			server = item
+			break
+		}
+	}
+
+	if server == nil {
+		err = ErrServerNotFound
+	}
+
+	return
+}
+
 func GetServersForUserID(user string) (servers map[string]*QpWhatsappServer) {
 	return WhatsappService.GetServersForUser(user)
 }
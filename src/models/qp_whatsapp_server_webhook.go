@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	library "github.com/nocodeleaks/quepasa/library"
 	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
 	log "github.com/sirupsen/logrus"
 )
@@ -75,3 +76,14 @@ func (source *QpWhatsappServerWebhook) ToggleForwardInternal() (handle bool, err
 	reason := fmt.Sprintf("toggle forward internal: %v", source.ForwardInternal)
 	return source.ForwardInternal, source.Save(reason)
 }
+
+// Generates a new secret for payload signatures, invalidating the previous one
+func (source *QpWhatsappServerWebhook) RotateSecret() (secret string, err error) {
+	secret, err = library.GenerateWebhookSecret()
+	if err != nil {
+		return
+	}
+
+	source.Secret = secret
+	return secret, source.Save("rotate secret")
+}
@@ -49,7 +49,20 @@ func QPWhatsappStart(logentry *log.Entry) error {
 			return err
 		}
 		// iniciando servidores e cada bot individualmente
-		return WhatsappService.Initialize()
+		err = WhatsappService.Initialize()
+		if err != nil {
+			return err
+		}
+
+		// applying retention policies on durable message store
+		if ENV.MessageStore() {
+			go WhatsappService.MessageStoreRetention()
+		}
+
+		// removing expired send idempotency keys
+		if ENV.IdempotencyHours() > 0 {
+			go WhatsappService.IdempotencyRetention()
+		}
 	} else {
 		logentry.Debug("attempt to start whatsapp service, already started ...")
 	}
@@ -142,6 +155,7 @@ func (source *QPWhatsappService) NewQpWhatsappServer(info *QpServer) (server *Qp
 		Reconnect:      true,
 		syncConnection: &sync.Mutex{},
 		syncMessages:   &sync.Mutex{},
+		syncChatwoot:   &sync.RWMutex{},
 		StartTime:      time.Now().UTC(),
 
 		StopRequested: false, // setting initial state
@@ -172,6 +186,11 @@ func (source *QPWhatsappService) NewQpWhatsappServer(info *QpServer) (server *Qp
 	server.HandlerEnsure()
 	server.WebHookEnsure()
 	server.WebhookFill(info, source.DB.Webhooks)
+	server.MessageStoreEnsure(source.DB.Messages)
+	server.WebhookQueueEnsure(source.DB.Deliveries)
+	server.SendQueueEnsure(source.DB.SendQueue)
+	server.AutoRulesEnsure(source.DB.AutoRules)
+	server.ChatwootEnsure()
 	return
 }
 
@@ -250,6 +269,58 @@ func (service *QPWhatsappService) GetOrCreateServer(user string, wid string) (re
 	return
 }
 
+/*
+<summary>
+
+	Creates and persists a server awaiting pairing, with a new token if not informed
+	* pair it on "/scan" or "/paircode" with the same token
+
+</summary>
+*/
+func (service *QPWhatsappService) CreateServer(info *QpServer) (server *QpWhatsappServer, err error) {
+	if len(info.Token) == 0 {
+		info.Token = uuid.New().String()
+	} else {
+		err = ValidateServerToken(info.Token)
+		if err != nil {
+			return
+		}
+	}
+
+	// avoiding concurrent creations with the same token
+	service.appendlock.Lock()
+	defer service.appendlock.Unlock()
+
+	if _, ok := service.Servers[info.Token]; ok {
+		return nil, fmt.Errorf("server already exists: %s", info.Token)
+	}
+
+	exists, err := service.DB.Servers.Exists(info.Token)
+	if err != nil {
+		return
+	}
+
+	if exists {
+		return nil, fmt.Errorf("server already exists: %s", info.Token)
+	}
+
+	info.Timestamp = time.Now().UTC()
+	err = service.DB.Servers.Add(info)
+	if err != nil {
+		return
+	}
+
+	server, err = service.AppendNewServer(info)
+	if err != nil {
+		deleteErr := service.DB.Servers.Delete(info.Token)
+		if deleteErr != nil {
+			logentry := service.GetLogger()
+			logentry.Warnf("error on removing not created server: %s, cause: %s", info.Token, deleteErr.Error())
+		}
+	}
+	return
+}
+
 // delete whatsapp server and remove from cache
 func (service *QPWhatsappService) Delete(server *QpWhatsappServer) (err error) {
 	err = server.Delete()
@@ -258,6 +329,36 @@ func (service *QPWhatsappService) Delete(server *QpWhatsappServer) (err error) {
 	}
 
 	delete(service.Servers, server.Token)
+
+	// stopping and removing webhook deliveries, pending and dead letters
+	if server.WebhookQueue != nil {
+		server.WebhookQueue.Stop()
+	}
+
+	err = service.DB.Deliveries.Clear(server.Token)
+	if err != nil {
+		logentry := service.GetLogger()
+		logentry.Warnf("error on clearing webhook deliveries for: %s, cause: %s", server.Token, err.Error())
+	}
+
+	// stopping and removing queued messages
+	if server.SendQueue != nil {
+		server.SendQueue.Stop()
+	}
+
+	err = service.DB.SendQueue.Clear(server.Token)
+	if err != nil {
+		logentry := service.GetLogger()
+		logentry.Warnf("error on clearing send queue for: %s, cause: %s", server.Token, err.Error())
+	}
+
+	// removing stored messages, if any
+	err = service.DB.Messages.Clear(server.Token)
+	if err != nil {
+		logentry := service.GetLogger()
+		logentry.Warnf("error on clearing stored messages for: %s, cause: %s", server.Token, err.Error())
+		err = nil
+	}
 	return
 }
 
@@ -322,3 +423,78 @@ func (source *QPWhatsappService) GetUser(username string, password string) (user
 	logger.Debugf("finding user: %s", username)
 	return source.DB.Users.Check(username, password)
 }
+
+//region MESSAGE STORE RETENTION
+
+const MessageStoreRetentionInterval = time.Hour
+
+// Loop that applies retention policies (days and length) on durable message store
+func (source *QPWhatsappService) MessageStoreRetention() {
+	for {
+		source.MessageStoreCleanUp()
+		time.Sleep(MessageStoreRetentionInterval)
+	}
+}
+
+// Removes old stored messages, by days and by maximum length for each server
+func (source *QPWhatsappService) MessageStoreCleanUp() {
+	logentry := source.GetLogger()
+
+	days := ENV.MessageStoreDays()
+	if days > 0 {
+		before := time.Now().UTC().Add(time.Duration(-days*24) * time.Hour)
+		affected, err := source.DB.Messages.CleanUp(before)
+		if err != nil {
+			logentry.Errorf("error on message store retention by days: %s", err.Error())
+		} else if affected > 0 {
+			logentry.Infof("message store retention by days, removed: %v", affected)
+		}
+	}
+
+	length := ENV.MessageStoreLength()
+	if length > 0 {
+		contexts, err := source.DB.Messages.Contexts()
+		if err != nil {
+			logentry.Errorf("error on message store retention by length: %s", err.Error())
+			return
+		}
+
+		for _, context := range contexts {
+			affected, err := source.DB.Messages.CleanUpLength(context, length)
+			if err != nil {
+				logentry.Errorf("error on message store retention by length, context: %s, cause: %s", context, err.Error())
+			} else if affected > 0 {
+				logentry.Infof("message store retention by length, context: %s, removed: %v", context, affected)
+			}
+		}
+	}
+}
+
+//endregion
+
+//region IDEMPOTENCY RETENTION
+
+const IdempotencyRetentionInterval = time.Hour
+
+// Loop that removes expired send idempotency keys
+func (source *QPWhatsappService) IdempotencyRetention() {
+	for {
+		source.IdempotencyCleanUp()
+		time.Sleep(IdempotencyRetentionInterval)
+	}
+}
+
+func (source *QPWhatsappService) IdempotencyCleanUp() {
+	logentry := source.GetLogger()
+
+	hours := ENV.IdempotencyHours()
+	before := time.Now().UTC().Add(time.Duration(-hours) * time.Hour)
+	affected, err := source.DB.Idempotency.CleanUp(before)
+	if err != nil {
+		logentry.Errorf("error on idempotency keys retention: %s", err.Error())
+	} else if affected > 0 {
+		logentry.Infof("idempotency keys retention, removed: %v", affected)
+	}
+}
+
+//endregion
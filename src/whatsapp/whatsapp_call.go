@@ -0,0 +1,42 @@
+package whatsapp
+
+import "time"
+
+// Call lifecycle states, emitted as call messages
+const (
+	WhatsappCallOffer     = "offer"
+	WhatsappCallAccept    = "accept"
+	WhatsappCallReject    = "reject"
+	WhatsappCallTerminate = "terminate"
+
+	// terminated without being accepted or rejected
+	WhatsappCallMissed = "missed"
+)
+
+// Call information for each lifecycle event
+type WhatsappCall struct {
+	Id    string `json:"id"`
+	State string `json:"state"`
+
+	Video bool `json:"video"`
+	Group bool `json:"group"`
+
+	// who started the call, useful for group calls
+	Creator string `json:"creator,omitempty"`
+
+	// caller client platform, on offer and accept
+	Platform string `json:"platform,omitempty"`
+
+	// terminate reason, if informed
+	Reason string `json:"reason,omitempty"`
+
+	// when the call was offered and accepted, if tracked
+	Offered  time.Time `json:"offered,omitempty"`
+	Accepted time.Time `json:"accepted,omitempty"`
+
+	// seconds between accept and terminate
+	Duration uint `json:"duration,omitempty"`
+
+	// rejected by this service (calls option or call rules) or on another device
+	Rejected bool `json:"rejected,omitempty"`
+}
@@ -0,0 +1,39 @@
+package whatsapp
+
+import "encoding/json"
+
+// Chat presence (typing indicators) sent to a chat
+type WhatsappChatPresenceType uint
+
+const (
+	WhatsappChatPresencePaused WhatsappChatPresenceType = iota
+	WhatsappChatPresenceTyping
+	WhatsappChatPresenceRecording
+)
+
+func (source WhatsappChatPresenceType) String() string {
+	switch source {
+	case WhatsappChatPresenceTyping:
+		return "typing"
+	case WhatsappChatPresenceRecording:
+		return "recording"
+	}
+
+	return "paused"
+}
+
+func (source WhatsappChatPresenceType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(source.String())
+}
+
+// Get chat presence from its string representation, default paused
+func GetChatPresenceFromString(value string) WhatsappChatPresenceType {
+	switch value {
+	case "typing", "composing":
+		return WhatsappChatPresenceTyping
+	case "recording":
+		return WhatsappChatPresenceRecording
+	}
+
+	return WhatsappChatPresencePaused
+}
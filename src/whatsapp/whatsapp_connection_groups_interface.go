@@ -0,0 +1,37 @@
+package whatsapp
+
+// Group management methods
+type IWhatsappConnectionGroups interface {
+
+	// Groups that this connection belongs to, with participants
+	GetJoinedGroups() ([]*WhatsappGroup, error)
+
+	GetGroupInfo(groupId string) (*WhatsappGroup, error)
+
+	// Creates a group, participants that fail are returned with error codes
+	CreateGroup(name string, participants []string) (*WhatsappGroup, error)
+
+	// Adds, removes, promotes or demotes participants
+	UpdateGroupParticipants(groupId string, participants []string, action WhatsappGroupParticipantAction) ([]*WhatsappGroupParticipant, error)
+
+	// Changes group subject
+	SetGroupName(groupId string, name string) error
+
+	// Changes group description
+	SetGroupTopic(groupId string, topic string) error
+
+	// Changes group picture (jpeg), returns new picture id
+	SetGroupPhoto(groupId string, content []byte) (string, error)
+
+	// Only admins can send messages
+	SetGroupAnnounce(groupId string, announce bool) error
+
+	// Only admins can edit group info
+	SetGroupLocked(groupId string, locked bool) error
+
+	// Revokes current invite link, returns the new one
+	RevokeInvite(groupId string) (string, error)
+
+	// Joins a group from invite code or link, returns group id
+	JoinGroup(code string) (string, error)
+}
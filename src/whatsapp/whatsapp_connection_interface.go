@@ -9,6 +9,8 @@ import (
 
 type IWhatsappConnection interface {
 	IWhatsappConnectionOptions
+	IWhatsappConnectionGroups
+	IWhatsappConnectionNewsletters
 
 	GetStatus() WhatsappConnectionState
 
@@ -37,6 +39,30 @@ type IWhatsappConnection interface {
 
 	Revoke(IWhatsappMessage) error
 
+	// Edit the text of a message sent by this connection
+	Edit(msg IWhatsappMessage, text string) error
+
+	// Send an emoji reaction to a message, empty reaction removes it
+	React(msg *WhatsappMessage, reaction string) error
+
+	// Send chat presence (typing, recording, paused) to a chat
+	SendChatPresence(chatId string, presence WhatsappChatPresenceType) error
+
+	// Set global presence (available or unavailable), kept on reconnections
+	SetPresence(available bool) error
+
+	// Subscribe to a contact presence updates, emitted as presence events
+	SubscribePresence(wid string) error
+
+	// Send read receipts for received messages
+	MarkRead(messages ...*WhatsappMessage) error
+
+	// Reject an incoming call, from caller wid and call id
+	RejectCall(from string, callId string) error
+
+	// Default status (stories) privacy, who receives published status
+	GetStatusPrivacy() (*WhatsappStatusPrivacy, error)
+
 	// Default send message method
 	Send(*WhatsappMessage) (IWhatsappSendResponse, error)
 
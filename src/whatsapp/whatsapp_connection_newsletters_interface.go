@@ -0,0 +1,19 @@
+package whatsapp
+
+// Newsletter (channels) methods
+type IWhatsappConnectionNewsletters interface {
+
+	// Newsletters that this connection follows or owns
+	GetSubscribedNewsletters() ([]*WhatsappNewsletter, error)
+
+	// Newsletter info from id or invite code/link
+	GetNewsletterInfo(newsletterId string) (*WhatsappNewsletter, error)
+
+	// Follows a newsletter from id or invite code/link, returns newsletter info
+	FollowNewsletter(newsletterId string) (*WhatsappNewsletter, error)
+
+	UnfollowNewsletter(newsletterId string) error
+
+	// Recent posts, before a server id (0 for latest)
+	GetNewsletterMessages(newsletterId string, count int, before int) ([]*WhatsappMessage, error)
+}
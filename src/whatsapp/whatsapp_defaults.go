@@ -7,6 +7,13 @@ const WhatsappReadReceipts = false // default read receipt option if none was sp
 const WhatsappCalls = true         // default calls option if none was specified
 const WhatsappGroups = true        // default group messages option if none was specified
 const WhatsappHistorySync = false  // default historysync option if none was specified
+const WhatsappNewsletters = false  // default newsletter (channels) messages option if none was specified
+
+// Chat id for publishing and receiving status (stories)
+const WhatsappStatusChatId = "status@broadcast"
+
+// Chat id suffix for newsletters (channels)
+const WhatsappNewsletterSuffix = "@newsletter"
 
 // Custom System name defined on start
 var WhatsappWebAppSystem string
@@ -11,6 +11,7 @@ import (
 var AllowedSuffix = map[string]bool{
 	"g.us":           true, // Mensagem para um grupo
 	"s.whatsapp.net": true, // Mensagem direta a um usuário
+	"newsletter":     true, // Newsletters (channels), posts only on owned ones
 }
 
 func PhoneToWid(source string) (destination string) {
@@ -46,6 +47,11 @@ func FormatEndpoint(source string) (destination string, err error) {
 		return
 	}
 
+	// status (stories), the only broadcast allowed
+	if destination == WhatsappStatusChatId {
+		return
+	}
+
 	if strings.ContainsAny(destination, "@") {
 		splited := strings.Split(destination, "@")
 		if !AllowedSuffix[splited[1]] {
@@ -0,0 +1,30 @@
+package whatsapp
+
+import "time"
+
+// Group metadata
+type WhatsappGroup struct {
+	Id    string `json:"id"`
+	Owner string `json:"owner,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Topic string `json:"topic,omitempty"`
+
+	// only admins can send messages
+	Announce bool `json:"announce"`
+
+	// only admins can edit group info
+	Locked bool `json:"locked"`
+
+	Created      time.Time                   `json:"created,omitempty"`
+	Participants []*WhatsappGroupParticipant `json:"participants,omitempty"`
+}
+
+type WhatsappGroupParticipant struct {
+	Id           string `json:"id"`
+	Lid          string `json:"lid,omitempty"`
+	IsAdmin      bool   `json:"admin,omitempty"`
+	IsSuperAdmin bool   `json:"superadmin,omitempty"`
+
+	// error code when a participant change fails, ex: 403 (privacy), 409 (already exists)
+	Error int `json:"error,omitempty"`
+}
@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+type WhatsappGroupParticipantAction uint
+
+const (
+	UnknownGroupParticipantAction WhatsappGroupParticipantAction = iota
+	AddGroupParticipantAction
+	RemoveGroupParticipantAction
+	PromoteGroupParticipantAction
+	DemoteGroupParticipantAction
+)
+
+func (source WhatsappGroupParticipantAction) String() string {
+	switch source {
+	case AddGroupParticipantAction:
+		return "add"
+	case RemoveGroupParticipantAction:
+		return "remove"
+	case PromoteGroupParticipantAction:
+		return "promote"
+	case DemoteGroupParticipantAction:
+		return "demote"
+	}
+
+	return "unknown"
+}
+
+func (source WhatsappGroupParticipantAction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(source.String())
+}
+
+// Get participant action from its string representation, default unknown
+func GetGroupParticipantActionFromString(value string) WhatsappGroupParticipantAction {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "add":
+		return AddGroupParticipantAction
+	case "remove":
+		return RemoveGroupParticipantAction
+	case "promote":
+		return PromoteGroupParticipantAction
+	case "demote":
+		return DemoteGroupParticipantAction
+	}
+
+	return UnknownGroupParticipantAction
+}
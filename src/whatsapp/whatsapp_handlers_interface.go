@@ -12,6 +12,9 @@ type IWhatsappHandlers interface {
 	// Update read receipt status
 	Receipt(*WhatsappMessage)
 
+	// Presence and chat presence (typing) updates, not cached
+	Presence(*WhatsappMessage)
+
 	// Event
 	LoggedOut(string)
 
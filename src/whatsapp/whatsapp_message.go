@@ -60,6 +60,21 @@ type WhatsappMessage struct {
 
 	Ads *WhatsappMessageAds `json:"ads,omitempty"`
 
+	// Poll question and options, or aggregated results for votes
+	Poll *WhatsappPoll `json:"poll,omitempty"`
+
+	// List menu with sections and rows
+	List *WhatsappList `json:"list,omitempty"`
+
+	// Quick reply buttons
+	Buttons *WhatsappButtons `json:"buttons,omitempty"`
+
+	// Call lifecycle event details
+	Call *WhatsappCall `json:"call,omitempty"`
+
+	// Status (stories) audience when publishing to a custom recipient list
+	Recipients []string `json:"recipients,omitempty"`
+
 	// Extra information for custom messages
 	Info interface{} `json:"info,omitempty"`
 }
@@ -142,17 +157,18 @@ func (source *WhatsappMessage) FromBroadcast() bool {
 		return true
 	}
 
-	if source.Chat.Id == "status@broadcast" {
-		return true
-	}
-
-	if strings.HasSuffix(source.Chat.Id, "@newsletter") {
+	if source.Chat.Id == WhatsappStatusChatId {
 		return true
 	}
 
 	return false
 }
 
+// Posts from newsletters (channels), not treated as broadcasts
+func (source *WhatsappMessage) FromNewsletter() bool {
+	return strings.HasSuffix(source.Chat.Id, WhatsappNewsletterSuffix)
+}
+
 func (source *WhatsappMessage) GetAttachment() *WhatsappAttachment {
 	return source.Attachment
 }
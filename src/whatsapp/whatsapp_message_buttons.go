@@ -0,0 +1,41 @@
+package whatsapp
+
+import "fmt"
+
+// maximum quick reply buttons accepted by whatsapp on a single message
+const WhatsappButtonsMax = 3
+
+// Quick reply buttons message
+type WhatsappButtons struct {
+	Text    string            `json:"text"`
+	Footer  string            `json:"footer,omitempty"`
+	Buttons []*WhatsappButton `json:"buttons"`
+}
+
+type WhatsappButton struct {
+	// Returned on response, text is used if empty
+	Id   string `json:"id,omitempty"`
+	Text string `json:"text"`
+}
+
+func (source *WhatsappButtons) Validate() error {
+	if len(source.Text) == 0 {
+		return fmt.Errorf("buttons text missing")
+	}
+
+	if len(source.Buttons) == 0 || len(source.Buttons) > WhatsappButtonsMax {
+		return fmt.Errorf("must have between 1 and %v buttons", WhatsappButtonsMax)
+	}
+
+	for _, button := range source.Buttons {
+		if len(button.Text) == 0 {
+			return fmt.Errorf("button text missing")
+		}
+
+		if len(button.Id) == 0 {
+			button.Id = button.Text
+		}
+	}
+
+	return nil
+}
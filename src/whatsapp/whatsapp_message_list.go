@@ -0,0 +1,59 @@
+package whatsapp
+
+import "fmt"
+
+// List menu message, opened by a single button
+type WhatsappList struct {
+	Title  string `json:"title,omitempty"`
+	Text   string `json:"text"`
+	Footer string `json:"footer,omitempty"`
+
+	// Text of the button that opens the menu
+	Button string `json:"button"`
+
+	Sections []*WhatsappListSection `json:"sections"`
+}
+
+type WhatsappListSection struct {
+	Title string             `json:"title,omitempty"`
+	Rows  []*WhatsappListRow `json:"rows"`
+}
+
+type WhatsappListRow struct {
+	// Returned on response, title is used if empty
+	Id          string `json:"id,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+func (source *WhatsappList) Validate() error {
+	if len(source.Text) == 0 {
+		return fmt.Errorf("list text missing")
+	}
+
+	if len(source.Button) == 0 {
+		return fmt.Errorf("list button text missing")
+	}
+
+	if len(source.Sections) == 0 {
+		return fmt.Errorf("list must have at least one section")
+	}
+
+	for _, section := range source.Sections {
+		if len(section.Rows) == 0 {
+			return fmt.Errorf("list section must have at least one row")
+		}
+
+		for _, row := range section.Rows {
+			if len(row.Title) == 0 {
+				return fmt.Errorf("list row title missing")
+			}
+
+			if len(row.Id) == 0 {
+				row.Id = row.Title
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// maximum options accepted by whatsapp on a single poll
+const WhatsappPollMaxOptions = 12
+
+type WhatsappPoll struct {
+	Question string   `json:"question"`
+	Options  []string `json:"options"`
+
+	// How many options can be selected, 0 for any
+	Selectable uint `json:"selectable,omitempty"`
+
+	// Latest selected options by voter wid, aggregated from received votes
+	Votes map[string][]string `json:"votes,omitempty"`
+
+	// Votes count by option
+	Results map[string]uint `json:"results,omitempty"`
+
+	mutex sync.Mutex
+}
+
+func (source *WhatsappPoll) Validate() error {
+	if len(source.Question) == 0 {
+		return fmt.Errorf("poll question missing")
+	}
+
+	if len(source.Options) < 2 || len(source.Options) > WhatsappPollMaxOptions {
+		return fmt.Errorf("poll must have between 2 and %v options", WhatsappPollMaxOptions)
+	}
+
+	if source.Selectable > uint(len(source.Options)) {
+		return fmt.Errorf("poll selectable count greater than options")
+	}
+
+	return nil
+}
+
+// Registers the latest vote of a voter (an empty selection removes it) and recounts results
+// Maps are replaced, never changed in place, previous snapshots remain valid
+func (source *WhatsappPoll) Vote(voter string, options []string) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	votes := make(map[string][]string, len(source.Votes)+1)
+	for key, selected := range source.Votes {
+		votes[key] = selected
+	}
+
+	if len(options) == 0 {
+		delete(votes, voter)
+	} else {
+		votes[voter] = options
+	}
+
+	results := make(map[string]uint, len(source.Options))
+	for _, option := range source.Options {
+		results[option] = 0
+	}
+
+	for _, selected := range votes {
+		for _, option := range selected {
+			results[option]++
+		}
+	}
+
+	source.Votes = votes
+	source.Results = results
+}
+
+// Copy of current state, safe to serialize while new votes arrive
+func (source *WhatsappPoll) Snapshot() *WhatsappPoll {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	result := &WhatsappPoll{
+		Question:   source.Question,
+		Options:    source.Options,
+		Selectable: source.Selectable,
+		Votes:      make(map[string][]string, len(source.Votes)),
+		Results:    make(map[string]uint, len(source.Results)),
+	}
+
+	for voter, options := range source.Votes {
+		result.Votes[voter] = options
+	}
+
+	for option, count := range source.Results {
+		result.Results[option] = count
+	}
+
+	return result
+}
+
+// used to marshal without recursion
+type whatsappPollJson WhatsappPoll
+
+// Serializes a locked snapshot, cached polls are encoded while votes arrive
+func (source *WhatsappPoll) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*whatsappPollJson)(source.Snapshot()))
+}
@@ -17,6 +17,11 @@ const (
 	SystemMessageType
 	GroupMessageType
 	RevokeMessageType
+	PollMessageType
+	PollVoteMessageType
+	ListMessageType
+	ButtonsMessageType
+	PresenceMessageType
 
 	// Messages that isn't important for this whatsapp service
 	DiscardMessageType
@@ -46,6 +51,16 @@ func (Type WhatsappMessageType) String() string {
 		return "group"
 	case RevokeMessageType:
 		return "revoke"
+	case PollMessageType:
+		return "poll"
+	case PollVoteMessageType:
+		return "pollvote"
+	case ListMessageType:
+		return "list"
+	case ButtonsMessageType:
+		return "buttons"
+	case PresenceMessageType:
+		return "presence"
 	case DiscardMessageType:
 		return "discard"
 	}
@@ -56,3 +71,33 @@ func (Type WhatsappMessageType) String() string {
 func (s WhatsappMessageType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.String())
 }
+
+// Get message type from its string representation, default unknown
+func GetMessageTypeFromString(value string) WhatsappMessageType {
+	for Type := UnknownMessageType; Type <= DiscardMessageType; Type++ {
+		if Type.String() == value {
+			return Type
+		}
+	}
+
+	return UnknownMessageType
+}
+
+// UnmarshalJSON parses fields that may be strings or numbers
+func (s *WhatsappMessageType) UnmarshalJSON(b []byte) error {
+	var text string
+	err := json.Unmarshal(b, &text)
+	if err == nil {
+		*s = GetMessageTypeFromString(text)
+		return nil
+	}
+
+	var number uint
+	err = json.Unmarshal(b, &number)
+	if err != nil {
+		return err
+	}
+
+	*s = WhatsappMessageType(number)
+	return nil
+}
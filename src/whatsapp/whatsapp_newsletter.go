@@ -0,0 +1,28 @@
+package whatsapp
+
+import "time"
+
+// Newsletter (channel) metadata
+type WhatsappNewsletter struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Invite      string    `json:"invite,omitempty"`
+	Subscribers int       `json:"subscribers"`
+	Verified    bool      `json:"verified"`
+	Created     time.Time `json:"created,omitempty"`
+
+	// active, suspended or geosuspended
+	State string `json:"state,omitempty"`
+
+	// viewer role: subscriber, guest, admin or owner
+	Role string `json:"role,omitempty"`
+
+	// viewer mute state
+	Muted bool `json:"muted"`
+}
+
+// Only owners and admins can post
+func (source *WhatsappNewsletter) CanPost() bool {
+	return source.Role == "owner" || source.Role == "admin"
+}
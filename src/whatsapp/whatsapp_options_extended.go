@@ -17,6 +17,9 @@ type WhatsappOptionsExtended struct {
 	// should handle calls
 	Calls WhatsappBooleanExtended `json:"calls,omitempty"`
 
+	// should handle newsletter (channels) posts, global only
+	Newsletters WhatsappBooleanExtended `json:"newsletters,omitempty"`
+
 	// should send markread requests
 	ReadUpdate bool `json:"readupdate"`
 
@@ -35,6 +38,7 @@ func (source WhatsappOptionsExtended) IsDefault() bool {
 		source.Broadcasts.Equals(UnSetBooleanType) &&
 		source.ReadReceipts.Equals(UnSetBooleanType) &&
 		source.Calls.Equals(UnSetBooleanType) &&
+		source.Newsletters.Equals(UnSetBooleanType) &&
 		!source.ReadUpdate &&
 		source.HistorySync == nil &&
 		len(source.LogLevel) == 0
@@ -108,6 +112,10 @@ func (source WhatsappOptionsExtended) HandleBroadcasts(local WhatsappBoolean) bo
 	}
 }
 
+func (source WhatsappOptionsExtended) HandleNewsletters() bool {
+	return source.Newsletters.ToBoolean(WhatsappNewsletters)
+}
+
 func (source WhatsappOptionsExtended) HandleHistory(mts uint64) bool {
 	if source.HistorySync != nil {
 		days := *source.HistorySync
@@ -0,0 +1,10 @@
+package whatsapp
+
+// Audience of published status (stories), defined on whatsapp privacy settings
+type WhatsappStatusPrivacy struct {
+	// contacts (all contacts), whitelist (only listed) or blacklist (contacts except listed)
+	Type string `json:"type"`
+
+	// Listed wids for whitelist or blacklist types
+	List []string `json:"list,omitempty"`
+}
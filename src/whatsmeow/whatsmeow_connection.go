@@ -192,7 +192,8 @@ func (source *WhatsmeowConnection) GetContacts() (chats []whatsapp.WhatsappChat,
 		return chats, err
 	}
 
-	contacts, err := source.Client.Store.Contacts.GetAllContacts()
+	// skipping status recipients, always listing stored contacts
+	contacts, err := GetStatusContactStore(source.Client.Store).ContactStore.GetAllContacts()
 	if err != nil {
 		return chats, err
 	}
@@ -297,7 +298,7 @@ func (source *WhatsmeowConnection) Revoke(msg whatsapp.IWhatsappMessage) error {
 	}
 
 	newMessage := source.Client.BuildRevoke(jid, participantJid, msg.GetId())
-	_, err = source.Client.SendMessage(context.Background(), jid, newMessage)
+	_, err = source.sendMessage(jid, newMessage)
 	if err != nil {
 		logentry.Infof("revoke error: %s", err)
 		return err
@@ -306,6 +307,239 @@ func (source *WhatsmeowConnection) Revoke(msg whatsapp.IWhatsappMessage) error {
 	return nil
 }
 
+func (source *WhatsmeowConnection) Edit(msg whatsapp.IWhatsappMessage, text string) error {
+	logentry := source.GetLogger()
+
+	jid, err := types.ParseJID(msg.GetChatId())
+	if err != nil {
+		logentry.Infof("edit error on get jid: %s", err)
+		return err
+	}
+
+	newContent := &waE2E.Message{Conversation: proto.String(text)}
+	newMessage := source.Client.BuildEdit(jid, msg.GetId(), newContent)
+	_, err = source.sendMessage(jid, newMessage)
+	if err != nil {
+		logentry.Infof("edit error: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+func (source *WhatsmeowConnection) React(msg *whatsapp.WhatsappMessage, reaction string) error {
+	logentry := source.GetLogger()
+
+	jid, err := types.ParseJID(msg.GetChatId())
+	if err != nil {
+		logentry.Infof("react error on get jid: %s", err)
+		return err
+	}
+
+	// sender of the original message, empty for own messages
+	sender := types.EmptyJID
+	if !msg.FromMe {
+		senderId := msg.GetParticipantId()
+		if len(senderId) == 0 {
+			senderId = msg.GetChatId()
+		}
+
+		sender, err = types.ParseJID(senderId)
+		if err != nil {
+			logentry.Infof("react error on get sender jid: %s", err)
+			return err
+		}
+	}
+
+	newMessage := source.Client.BuildReaction(jid, sender, msg.GetId(), reaction)
+	_, err = source.sendMessage(jid, newMessage)
+	if err != nil {
+		logentry.Infof("react error: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+func (source *WhatsmeowConnection) SendChatPresence(chatId string, presence whatsapp.WhatsappChatPresenceType) error {
+	jid, err := types.ParseJID(chatId)
+	if err != nil {
+		source.GetLogger().Infof("chat presence error on get jid: %s", err)
+		return err
+	}
+
+	state := types.ChatPresenceComposing
+	media := types.ChatPresenceMediaText
+	switch presence {
+	case whatsapp.WhatsappChatPresencePaused:
+		state = types.ChatPresencePaused
+	case whatsapp.WhatsappChatPresenceRecording:
+		media = types.ChatPresenceMediaAudio
+	}
+
+	return source.Client.SendChatPresence(jid, state, media)
+}
+
+func (source *WhatsmeowConnection) SetPresence(available bool) error {
+	presence := types.PresenceUnavailable
+	if available {
+		presence = types.PresenceAvailable
+	}
+
+	err := source.Client.SendPresence(presence)
+	if err != nil {
+		return err
+	}
+
+	// keeping for next connected events
+	source.Handlers.WhatsmeowOptions.Presence = string(presence)
+	return nil
+}
+
+func (source *WhatsmeowConnection) SubscribePresence(wid string) error {
+	formatted, err := whatsapp.FormatEndpoint(wid)
+	if err != nil {
+		return err
+	}
+
+	jid, err := types.ParseJID(formatted)
+	if err != nil {
+		return err
+	}
+
+	return source.Client.SubscribePresence(jid)
+}
+
+// Sends read receipts, grouped by chat and sender as required by whatsapp
+func (source *WhatsmeowConnection) MarkRead(messages ...*whatsapp.WhatsappMessage) error {
+	type receipt struct {
+		chat   types.JID
+		sender types.JID
+		ids    []types.MessageID
+	}
+
+	var receipts []*receipt
+	grouped := make(map[string]*receipt)
+	for _, message := range messages {
+		key := message.Chat.Id + "|" + message.GetParticipantId()
+		element, ok := grouped[key]
+		if !ok {
+			chat, err := types.ParseJID(message.Chat.Id)
+			if err != nil {
+				return err
+			}
+
+			element = &receipt{chat: chat}
+			if message.Participant != nil {
+				element.sender, err = types.ParseJID(message.Participant.Id)
+				if err != nil {
+					return err
+				}
+			}
+
+			grouped[key] = element
+			receipts = append(receipts, element)
+		}
+
+		element.ids = append(element.ids, message.Id)
+	}
+
+	readtime := time.Now()
+	for _, element := range receipts {
+		err := source.Client.MarkRead(element.ids, readtime, element.chat, element.sender)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (source *WhatsmeowConnection) RejectCall(from string, callId string) error {
+	jid, err := types.ParseJID(from)
+	if err != nil {
+		return err
+	}
+
+	err = source.Client.RejectCall(jid, callId)
+	if err != nil {
+		return err
+	}
+
+	source.Handlers.CallRejected(callId)
+	return nil
+}
+
+func (source *WhatsmeowConnection) GetStatusPrivacy() (*whatsapp.WhatsappStatusPrivacy, error) {
+	options, err := source.Client.GetStatusPrivacy()
+	if err != nil {
+		return nil, err
+	}
+
+	// first one is always the default
+	privacy := &whatsapp.WhatsappStatusPrivacy{}
+	if len(options) > 0 {
+		privacy.Type = string(options[0].Type)
+		for _, jid := range options[0].List {
+			privacy.List = append(privacy.List, jid.String())
+		}
+	}
+
+	return privacy, nil
+}
+
+/*
+<summary>
+
+	Publishes a status (stories) only to informed recipients
+	Not available with whitelist privacy, whatsmeow always uses the whitelisted contacts in that case
+	Blacklisted contacts on privacy settings still not receives it
+
+</summary>
+*/
+func (source *WhatsmeowConnection) SendStatusToRecipients(recipients []string, message *waE2E.Message, extra whatsmeow.SendRequestExtra) (resp whatsmeow.SendResponse, err error) {
+	options, err := source.Client.GetStatusPrivacy()
+	if err != nil {
+		return
+	}
+
+	if len(options) > 0 && options[0].Type == types.StatusPrivacyTypeWhitelist {
+		err = fmt.Errorf("custom recipients are not available with whitelist status privacy, change it to contacts or blacklist")
+		return
+	}
+
+	var jids []types.JID
+	for _, recipient := range recipients {
+		jid, jiderr := types.ParseJID(recipient)
+		if jiderr != nil {
+			err = fmt.Errorf("invalid status recipient: %s, %s", recipient, jiderr.Error())
+			return
+		}
+		jids = append(jids, jid)
+	}
+
+	contacts := GetStatusContactStore(source.Client.Store)
+	err = contacts.WithRecipients(jids, func() (senderr error) {
+		resp, senderr = source.Client.SendMessage(context.Background(), types.StatusBroadcastJID, message, extra)
+		return
+	})
+	return
+}
+
+// Sends through whatsmeow client, status broadcasts wait for any publishing restricted to recipients
+func (source *WhatsmeowConnection) sendMessage(to types.JID, message *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (resp whatsmeow.SendResponse, err error) {
+	if to != types.StatusBroadcastJID {
+		return source.Client.SendMessage(context.Background(), to, message, extra...)
+	}
+
+	contacts := GetStatusContactStore(source.Client.Store)
+	err = contacts.Publish(func() (senderr error) {
+		resp, senderr = source.Client.SendMessage(context.Background(), to, message, extra...)
+		return
+	})
+	return
+}
+
 func (conn *WhatsmeowConnection) IsOnWhatsApp(phones ...string) (registered []string, err error) {
 	results, err := conn.Client.IsOnWhatsApp(phones)
 	if err != nil {
@@ -441,8 +675,21 @@ func (source *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp
 	// request message text
 	messageText := msg.GetText()
 
+	extra := whatsmeow.SendRequestExtra{}
+
 	var newMessage *waE2E.Message
-	if !msg.HasAttachment() {
+	if msg.Poll != nil {
+		newMessage = source.Client.BuildPollCreation(msg.Poll.Question, msg.Poll.Options, int(msg.Poll.Selectable))
+		newMessage.PollCreationMessage.ContextInfo = source.GetInReplyContextInfo(*msg)
+	} else if msg.List != nil {
+		internal := GenerateListMessage(msg.List)
+		internal.ContextInfo = source.GetInReplyContextInfo(*msg)
+		newMessage = &waE2E.Message{ListMessage: internal}
+	} else if msg.Buttons != nil {
+		internal := GenerateInteractiveButtonsMessage(msg.Buttons)
+		internal.ContextInfo = source.GetInReplyContextInfo(*msg)
+		newMessage = &waE2E.Message{ButtonsMessage: internal}
+	} else if !msg.HasAttachment() {
 		if IsValidForButtons(messageText) {
 			internal := GenerateButtonsMessage(messageText)
 			newMessage = &waE2E.Message{ButtonsMessage: internal}
@@ -452,6 +699,11 @@ func (source *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp
 
 			newMessage = &waE2E.Message{ExtendedTextMessage: internal}
 		}
+	} else if jid.Server == types.NewsletterServer {
+		newMessage, extra.MediaHandle, err = source.UploadNewsletterAttachment(*msg)
+		if err != nil {
+			return msg, err
+		}
 	} else {
 		newMessage, err = source.UploadAttachment(*msg)
 		if err != nil {
@@ -464,16 +716,20 @@ func (source *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp
 		msg.Id = source.Client.GenerateMessageID()
 	}
 
-	extra := whatsmeow.SendRequestExtra{
-		ID: msg.Id,
-	}
+	extra.ID = msg.Id
 
 	// saving cached content for instance of future reply
 	if msg.Content == nil {
 		msg.Content = newMessage
 	}
 
-	resp, err := source.Client.SendMessage(context.Background(), jid, newMessage, extra)
+	var resp whatsmeow.SendResponse
+	if len(msg.Recipients) > 0 {
+		resp, err = source.SendStatusToRecipients(msg.Recipients, newMessage, extra)
+	} else {
+		resp, err = source.sendMessage(jid, newMessage, extra)
+	}
+
 	if err != nil {
 		logentry.Errorf("whatsmeow connection send error: %s", err)
 		return msg, err
@@ -489,7 +745,7 @@ func (source *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp
 	}
 
 	// testing, mark read function
-	if source.Handlers.ReadUpdate {
+	if source.Handlers.ReadUpdate && jid.Server != types.NewsletterServer {
 		go source.Handlers.MarkRead(msg, types.ReceiptTypeRead)
 	}
 
@@ -533,6 +789,26 @@ func (source *WhatsmeowConnection) UploadAttachment(msg whatsapp.WhatsappMessage
 	return
 }
 
+// newsletters media are not encrypted, the upload handle must be informed on send
+func (source *WhatsmeowConnection) UploadNewsletterAttachment(msg whatsapp.WhatsappMessage) (result *waE2E.Message, handle string, err error) {
+
+	content := *msg.Attachment.GetContent()
+	if len(content) == 0 {
+		err = fmt.Errorf("null or empty content")
+		return
+	}
+
+	mediaType := GetMediaTypeFromWAMsgType(msg.Type)
+	response, err := source.Client.UploadNewsletter(context.Background(), content, mediaType)
+	if err != nil {
+		return
+	}
+
+	result = NewWhatsmeowMessageAttachment(response, msg, mediaType, nil)
+	handle = response.Handle
+	return
+}
+
 func (conn *WhatsmeowConnection) Disconnect() (err error) {
 	if conn.Client != nil {
 		if conn.Client.IsConnected() {
@@ -0,0 +1,215 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"strings"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "go.mau.fi/whatsmeow"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+//#region IMPLEMENT WHATSAPP CONNECTION GROUPS INTERFACE
+
+const WhatsappInviteLinkPrefix = "https://chat.whatsapp.com/"
+
+func ToWhatsappGroup(info *types.GroupInfo) *whatsapp.WhatsappGroup {
+	group := &whatsapp.WhatsappGroup{
+		Id:       info.JID.String(),
+		Name:     info.Name,
+		Topic:    info.Topic,
+		Announce: info.IsAnnounce,
+		Locked:   info.IsLocked,
+		Created:  info.GroupCreated,
+	}
+
+	if !info.OwnerJID.IsEmpty() {
+		group.Owner = info.OwnerJID.String()
+	}
+
+	group.Participants = ToWhatsappGroupParticipants(info.Participants)
+	return group
+}
+
+func ToWhatsappGroupParticipants(participants []types.GroupParticipant) (result []*whatsapp.WhatsappGroupParticipant) {
+	for _, participant := range participants {
+		element := &whatsapp.WhatsappGroupParticipant{
+			Id:           participant.JID.String(),
+			IsAdmin:      participant.IsAdmin,
+			IsSuperAdmin: participant.IsSuperAdmin,
+			Error:        participant.Error,
+		}
+
+		if !participant.LID.IsEmpty() {
+			element.Lid = participant.LID.String()
+		}
+
+		result = append(result, element)
+	}
+	return
+}
+
+// parses group id and participants (phone numbers or wids) to jids
+func getGroupJIDs(groupId string, participants []string) (group types.JID, jids []types.JID, err error) {
+	if len(groupId) > 0 {
+		group, err = types.ParseJID(groupId)
+		if err != nil {
+			return
+		}
+	}
+
+	for _, participant := range participants {
+		formatted, err := whatsapp.FormatEndpoint(participant)
+		if err != nil {
+			return group, nil, err
+		}
+
+		jid, err := types.ParseJID(formatted)
+		if err != nil {
+			return group, nil, err
+		}
+
+		jids = append(jids, jid)
+	}
+	return
+}
+
+func (source *WhatsmeowConnection) GetJoinedGroups() (groups []*whatsapp.WhatsappGroup, err error) {
+	infos, err := source.Client.GetJoinedGroups()
+	if err != nil {
+		return
+	}
+
+	for _, info := range infos {
+		groups = append(groups, ToWhatsappGroup(info))
+	}
+	return
+}
+
+func (source *WhatsmeowConnection) GetGroupInfo(groupId string) (*whatsapp.WhatsappGroup, error) {
+	jid, err := types.ParseJID(groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := source.Client.GetGroupInfo(jid)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToWhatsappGroup(info), nil
+}
+
+func (source *WhatsmeowConnection) CreateGroup(name string, participants []string) (*whatsapp.WhatsappGroup, error) {
+	_, jids, err := getGroupJIDs("", participants)
+	if err != nil {
+		return nil, err
+	}
+
+	request := whatsmeow.ReqCreateGroup{
+		Name:         name,
+		Participants: jids,
+	}
+
+	info, err := source.Client.CreateGroup(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToWhatsappGroup(info), nil
+}
+
+func (source *WhatsmeowConnection) UpdateGroupParticipants(groupId string, participants []string, action whatsapp.WhatsappGroupParticipantAction) ([]*whatsapp.WhatsappGroupParticipant, error) {
+	var change whatsmeow.ParticipantChange
+	switch action {
+	case whatsapp.AddGroupParticipantAction:
+		change = whatsmeow.ParticipantChangeAdd
+	case whatsapp.RemoveGroupParticipantAction:
+		change = whatsmeow.ParticipantChangeRemove
+	case whatsapp.PromoteGroupParticipantAction:
+		change = whatsmeow.ParticipantChangePromote
+	case whatsapp.DemoteGroupParticipantAction:
+		change = whatsmeow.ParticipantChangeDemote
+	default:
+		return nil, fmt.Errorf("invalid participant action: %s", action)
+	}
+
+	jid, jids, err := getGroupJIDs(groupId, participants)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := source.Client.UpdateGroupParticipants(jid, jids, change)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToWhatsappGroupParticipants(result), nil
+}
+
+func (source *WhatsmeowConnection) SetGroupName(groupId string, name string) error {
+	jid, err := types.ParseJID(groupId)
+	if err != nil {
+		return err
+	}
+
+	return source.Client.SetGroupName(jid, name)
+}
+
+func (source *WhatsmeowConnection) SetGroupTopic(groupId string, topic string) error {
+	jid, err := types.ParseJID(groupId)
+	if err != nil {
+		return err
+	}
+
+	return source.Client.SetGroupDescription(jid, topic)
+}
+
+func (source *WhatsmeowConnection) SetGroupPhoto(groupId string, content []byte) (string, error) {
+	jid, err := types.ParseJID(groupId)
+	if err != nil {
+		return "", err
+	}
+
+	return source.Client.SetGroupPhoto(jid, content)
+}
+
+func (source *WhatsmeowConnection) SetGroupAnnounce(groupId string, announce bool) error {
+	jid, err := types.ParseJID(groupId)
+	if err != nil {
+		return err
+	}
+
+	return source.Client.SetGroupAnnounce(jid, announce)
+}
+
+func (source *WhatsmeowConnection) SetGroupLocked(groupId string, locked bool) error {
+	jid, err := types.ParseJID(groupId)
+	if err != nil {
+		return err
+	}
+
+	return source.Client.SetGroupLocked(jid, locked)
+}
+
+func (source *WhatsmeowConnection) RevokeInvite(groupId string) (string, error) {
+	jid, err := types.ParseJID(groupId)
+	if err != nil {
+		return "", err
+	}
+
+	return source.Client.GetGroupInviteLink(jid, true)
+}
+
+func (source *WhatsmeowConnection) JoinGroup(code string) (string, error) {
+	code = strings.TrimPrefix(strings.TrimSpace(code), WhatsappInviteLinkPrefix)
+
+	jid, err := source.Client.JoinGroupWithLink(code)
+	if err != nil {
+		return "", err
+	}
+
+	return jid.String(), nil
+}
+
+//#endregion
@@ -0,0 +1,138 @@
+package whatsmeow
+
+import (
+	"fmt"
+	"strings"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "go.mau.fi/whatsmeow"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+//#region IMPLEMENT WHATSAPP CONNECTION NEWSLETTERS INTERFACE
+
+const WhatsappNewsletterLinkPrefix = "https://whatsapp.com/channel/"
+
+func ToWhatsappNewsletter(info *types.NewsletterMetadata) *whatsapp.WhatsappNewsletter {
+	newsletter := &whatsapp.WhatsappNewsletter{
+		Id:          info.ID.String(),
+		Name:        info.ThreadMeta.Name.Text,
+		Description: info.ThreadMeta.Description.Text,
+		Invite:      info.ThreadMeta.InviteCode,
+		Subscribers: info.ThreadMeta.SubscriberCount,
+		Verified:    info.ThreadMeta.VerificationState == types.NewsletterVerificationStateVerified,
+		Created:     info.ThreadMeta.CreationTime.Time,
+		State:       string(info.State.Type),
+	}
+
+	if info.ViewerMeta != nil {
+		newsletter.Role = string(info.ViewerMeta.Role)
+		newsletter.Muted = info.ViewerMeta.Mute == types.NewsletterMuteOn
+	}
+
+	return newsletter
+}
+
+// gets newsletter metadata from a jid or an invite code/link
+func (source *WhatsmeowConnection) getNewsletterMetadata(newsletterId string) (*types.NewsletterMetadata, error) {
+	newsletterId = strings.TrimSpace(newsletterId)
+	if strings.HasSuffix(newsletterId, whatsapp.WhatsappNewsletterSuffix) {
+		jid, err := types.ParseJID(newsletterId)
+		if err != nil {
+			return nil, err
+		}
+
+		return source.Client.GetNewsletterInfo(jid)
+	}
+
+	code := strings.TrimPrefix(newsletterId, WhatsappNewsletterLinkPrefix)
+	if len(code) == 0 {
+		return nil, fmt.Errorf("newsletter id or invite missing")
+	}
+
+	return source.Client.GetNewsletterInfoWithInvite(code)
+}
+
+func (source *WhatsmeowConnection) GetSubscribedNewsletters() (newsletters []*whatsapp.WhatsappNewsletter, err error) {
+	infos, err := source.Client.GetSubscribedNewsletters()
+	if err != nil {
+		return
+	}
+
+	for _, info := range infos {
+		newsletters = append(newsletters, ToWhatsappNewsletter(info))
+	}
+	return
+}
+
+func (source *WhatsmeowConnection) GetNewsletterInfo(newsletterId string) (*whatsapp.WhatsappNewsletter, error) {
+	info, err := source.getNewsletterMetadata(newsletterId)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToWhatsappNewsletter(info), nil
+}
+
+func (source *WhatsmeowConnection) FollowNewsletter(newsletterId string) (*whatsapp.WhatsappNewsletter, error) {
+	info, err := source.getNewsletterMetadata(newsletterId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = source.Client.FollowNewsletter(info.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToWhatsappNewsletter(info), nil
+}
+
+func (source *WhatsmeowConnection) UnfollowNewsletter(newsletterId string) error {
+	jid, err := types.ParseJID(newsletterId)
+	if err != nil {
+		return err
+	}
+
+	return source.Client.UnfollowNewsletter(jid)
+}
+
+func (source *WhatsmeowConnection) GetNewsletterMessages(newsletterId string, count int, before int) (messages []*whatsapp.WhatsappMessage, err error) {
+	jid, err := types.ParseJID(newsletterId)
+	if err != nil {
+		return
+	}
+
+	params := &whatsmeow.GetNewsletterMessagesParams{
+		Count:  count,
+		Before: types.MessageServerID(before),
+	}
+
+	posts, err := source.Client.GetNewsletterMessages(jid, params)
+	if err != nil {
+		return
+	}
+
+	for _, post := range posts {
+		if post.Message == nil {
+			continue
+		}
+
+		// newsletter posts are identified by server ids
+		message := &whatsapp.WhatsappMessage{
+			Id:      fmt.Sprint(post.MessageServerID),
+			Content: post.Message,
+			Chat:    whatsapp.WhatsappChat{Id: jid.String()},
+		}
+
+		HandleKnowingMessages(source.Handlers, message, post.Message)
+		if message.Type == whatsapp.DiscardMessageType {
+			continue
+		}
+
+		messages = append(messages, message)
+	}
+	return
+}
+
+//#endregion
@@ -229,6 +229,11 @@ func IsValidForButtons(text string) bool {
 	return false
 }
 
+// Generates a new unique message id, same format used on send
+func GenerateMessageId() string {
+	return whatsmeow.GenerateMessageID()
+}
+
 /*
 <summary>
 
@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	whatsmeow "go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
+	binary "go.mau.fi/whatsmeow/binary"
 	types "go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
@@ -36,6 +38,9 @@ type WhatsmeowHandlers struct {
 
 	// events counter
 	Counter uint64
+
+	// tracked calls by id, for lifecycle states and duration
+	calls sync.Map
 }
 
 func (source *WhatsmeowHandlers) GetServiceOptions() (options whatsapp.WhatsappOptionsExtended) {
@@ -117,7 +122,7 @@ func (source *WhatsmeowHandlers) HandleCalls() bool {
 
 //#endregion
 
-func (source WhatsmeowHandlers) HandleHistorySync() bool {
+func (source *WhatsmeowHandlers) HandleHistorySync() bool {
 	options := source.GetServiceOptions()
 	if options.HistorySync != nil {
 		return true
@@ -185,12 +190,27 @@ func (source *WhatsmeowHandlers) EventsHandler(rawEvt interface{}) {
 		//# region CALLS
 	case *events.CallOffer:
 		logentry.Infof("CallOffer: %v", evt)
-		go source.CallMessage(evt.BasicCallMeta)
+		source.CallOffer(evt.BasicCallMeta, evt.RemotePlatform, hasCallChild(evt.Data, "video"), hasCallChild(evt.Data, "group_info"))
 		return
 
 	case *events.CallOfferNotice:
 		logentry.Infof("CallOfferNotice: %v", evt)
-		go source.CallMessage(evt.BasicCallMeta)
+		source.CallOffer(evt.BasicCallMeta, "", evt.Media == "video", evt.Type == "group")
+		return
+
+	case *events.CallAccept:
+		logentry.Infof("CallAccept: %v", evt)
+		source.CallUpdate(evt.BasicCallMeta, whatsapp.WhatsappCallAccept, evt.RemotePlatform)
+		return
+
+	case *events.CallReject:
+		logentry.Infof("CallReject: %v", evt)
+		source.CallUpdate(evt.BasicCallMeta, whatsapp.WhatsappCallReject, "")
+		return
+
+	case *events.CallTerminate:
+		logentry.Infof("CallTerminate: %v", evt)
+		source.CallUpdate(evt.BasicCallMeta, whatsapp.WhatsappCallTerminate, evt.Reason)
 		return
 
 	/*
@@ -204,6 +224,14 @@ func (source *WhatsmeowHandlers) EventsHandler(rawEvt interface{}) {
 		go source.Receipt(*evt)
 		return
 
+	case *events.Presence:
+		go source.Presence(*evt)
+		return
+
+	case *events.ChatPresence:
+		go source.ChatPresence(*evt)
+		return
+
 	case *events.Connected:
 		if source.Client != nil {
 			// zerando contador de tentativas de reconexão
@@ -265,7 +293,6 @@ func (source *WhatsmeowHandlers) EventsHandler(rawEvt interface{}) {
 
 	case
 		*events.AppState,
-		*events.CallTerminate,
 		*events.DeleteChat,
 		*events.DeleteForMe,
 		*events.MarkChatAsRead,
@@ -399,6 +426,11 @@ func (handler *WhatsmeowHandlers) Message(evt events.Message, from string) {
 	// Process diferent message types
 	HandleKnowingMessages(handler, message, evt.Message)
 
+	// poll votes must be decrypted and aggregated with the original poll
+	if message.Type == whatsapp.PollVoteMessageType {
+		handler.PollVote(message, &evt)
+	}
+
 	// discard and return
 	if message.Type == whatsapp.DiscardMessageType {
 		JsonMsg := ToJson(evt)
@@ -414,6 +446,42 @@ func (handler *WhatsmeowHandlers) Message(evt events.Message, from string) {
 	handler.Follow(message, from)
 }
 
+// Decrypts a poll vote, resolving selected options and aggregating results on the cached poll
+func (handler *WhatsmeowHandlers) PollVote(message *whatsapp.WhatsappMessage, evt *events.Message) {
+	logentry := handler.GetLogger()
+	logentry = logentry.WithField(LogFields.MessageId, message.Id)
+
+	vote, err := handler.Client.DecryptPollVote(evt)
+	if err != nil {
+		logentry.Warnf("error on decrypting poll vote, poll id: %s, cause: %s", message.InReply, err.Error())
+		return
+	}
+
+	voter := message.Chat.Id
+	if message.Participant != nil {
+		voter = message.Participant.Id
+	}
+
+	var poll *whatsapp.WhatsappPoll
+	if handler.WAHandlers != nil && !handler.WAHandlers.IsInterfaceNil() {
+		cached, _ := handler.WAHandlers.GetById(message.InReply)
+		if cached != nil {
+			poll = cached.Poll
+		}
+	}
+
+	if poll == nil {
+		logentry.Warnf("poll not cached, vote options can not be resolved, poll id: %s", message.InReply)
+		poll = &whatsapp.WhatsappPoll{}
+	}
+
+	selected := GetPollSelectedOptions(poll.Options, vote.GetSelectedOptions())
+	poll.Vote(voter, selected)
+
+	message.Text = strings.Join(selected, ", ")
+	message.Poll = poll.Snapshot()
+}
+
 func ToJson(in interface{}) string {
 	bytes, err := json.Marshal(in)
 	if err == nil {
@@ -445,7 +513,7 @@ func (handler *WhatsmeowHandlers) Follow(message *whatsapp.WhatsappMessage, from
 	}
 
 	// testing, mark read function
-	if handler.WhatsappOptionsExtended.ReadUpdate && !message.FromBroadcast() {
+	if handler.WhatsappOptionsExtended.ReadUpdate && !message.FromBroadcast() && !message.FromNewsletter() {
 		go handler.MarkRead(message, types.ReceiptTypeRead)
 	}
 }
@@ -483,38 +551,135 @@ func (handler *WhatsmeowHandlers) MarkRead(message *whatsapp.WhatsappMessage, re
 
 //#region EVENT CALL
 
-func (source *WhatsmeowHandlers) CallMessage(evt types.BasicCallMeta) {
+// indicates that a call node has a child element, ex: video or group_info
+func hasCallChild(node *binary.Node, tag string) bool {
+	if node == nil {
+		return false
+	}
+
+	_, ok := node.GetOptionalChildByTag(tag)
+	return ok
+}
+
+// Incoming call offer, starts tracking the call lifecycle
+func (source *WhatsmeowHandlers) CallOffer(evt types.BasicCallMeta, platform string, video bool, group bool) {
 	logentry := source.GetLogger()
-	logentry.Trace("event CallMessage !")
+	logentry.Trace("event CallOffer !")
+
+	source.CleanupCalls()
+
+	call := &whatsapp.WhatsappCall{
+		Id:       evt.CallID,
+		State:    whatsapp.WhatsappCallOffer,
+		Video:    video,
+		Group:    group,
+		Platform: platform,
+		Offered:  evt.Timestamp,
+	}
+
+	if !evt.CallCreator.IsEmpty() {
+		call.Creator = evt.CallCreator.ToNonAD().String()
+	}
+
+	// should reject this call
+	if !source.HandleCalls() {
+		call.Rejected = true
+		go func() {
+			err := source.Client.RejectCall(evt.From, evt.CallID)
+			if err != nil {
+				logentry.Errorf("error on rejecting call: %s", err.Error())
+			} else {
+				logentry.Infof("rejecting incoming call from: %s", evt.From)
+			}
+		}()
+	}
+
+	source.calls.Store(evt.CallID, call)
+	source.CallMessage(evt, *call)
+}
+
+// Call accept, reject or terminate, updates the tracked call
+func (source *WhatsmeowHandlers) CallUpdate(evt types.BasicCallMeta, state string, info string) {
+	logentry := source.GetLogger()
+	logentry.Tracef("event Call%s !", state)
+
+	call := &whatsapp.WhatsappCall{Id: evt.CallID}
+	if value, ok := source.calls.Load(evt.CallID); ok {
+		tracked := *value.(*whatsapp.WhatsappCall)
+		call = &tracked
+	}
+
+	call.State = state
+	switch state {
+	case whatsapp.WhatsappCallAccept:
+		call.Accepted = evt.Timestamp
+		if len(info) > 0 {
+			call.Platform = info
+		}
+		source.calls.Store(evt.CallID, call)
+	case whatsapp.WhatsappCallReject:
+		// rejected on another device, terminate should not report as missed
+		call.Rejected = true
+		source.calls.Store(evt.CallID, call)
+	case whatsapp.WhatsappCallTerminate:
+		source.calls.Delete(evt.CallID)
+
+		call.Reason = info
+		if !call.Accepted.IsZero() {
+			call.Duration = uint(evt.Timestamp.Sub(call.Accepted).Seconds())
+		} else if !call.Rejected && !call.Offered.IsZero() {
+			call.State = whatsapp.WhatsappCallMissed
+		}
+	}
+
+	source.CallMessage(evt, *call)
+}
+
+// Marks a tracked call as rejected by this service
+func (source *WhatsmeowHandlers) CallRejected(callId string) {
+	if value, ok := source.calls.Load(callId); ok {
+		tracked := *value.(*whatsapp.WhatsappCall)
+		tracked.Rejected = true
+		source.calls.Store(callId, &tracked)
+	}
+}
+
+// Removes tracked calls without terminate events
+func (source *WhatsmeowHandlers) CleanupCalls() {
+	limit := time.Now().Add(-1 * time.Hour)
+	source.calls.Range(func(key, value any) bool {
+		if value.(*whatsapp.WhatsappCall).Offered.Before(limit) {
+			source.calls.Delete(key)
+		}
+		return true
+	})
+}
 
+// Emits a call lifecycle event as a call message
+func (source *WhatsmeowHandlers) CallMessage(evt types.BasicCallMeta, call whatsapp.WhatsappCall) {
 	message := &whatsapp.WhatsappMessage{Content: evt}
 
-	// basic information
+	// basic information, offer keeps call id for compatibility
 	message.Id = evt.CallID
+	if call.State != whatsapp.WhatsappCallOffer {
+		message.Id = evt.CallID + "-" + call.State
+	}
+
 	message.Timestamp = evt.Timestamp
-	message.FromMe = false
+	message.FromMe = source.Client.Store.ID != nil && evt.CallCreator.User == source.Client.Store.ID.User
 
 	message.Chat = whatsapp.WhatsappChat{}
 	chatID := fmt.Sprint(evt.From.User, "@", evt.From.Server)
 	message.Chat.Id = chatID
 
 	message.Type = whatsapp.CallMessageType
+	message.Call = &call
 
 	if source.WAHandlers != nil {
 
 		// following to internal handlers
 		go source.WAHandlers.Message(message, "call")
 	}
-
-	// should reject this call
-	if !source.HandleCalls() {
-		err := source.Client.RejectCall(evt.From, evt.CallID)
-		if err != nil {
-			logentry.Errorf("error on rejecting call: %s", err.Error())
-		} else {
-			logentry.Infof("rejecting incoming call from: %s", evt.From)
-		}
-	}
 }
 
 /*
@@ -552,6 +717,12 @@ func (source *WhatsmeowHandlers) Receipt(evt events.Receipt) {
 
 	chatID := fmt.Sprint(evt.Chat.User, "@", evt.Chat.Server)
 
+	// views of our published status (stories)
+	if chatID == whatsapp.WhatsappStatusChatId {
+		source.StatusView(evt)
+		return
+	}
+
 	// Ignore chats with @broadcast and @newsletter
 	if strings.Contains(chatID, "@broadcast") || strings.Contains(chatID, "@newsletter") {
 		return
@@ -613,6 +784,94 @@ func (source *WhatsmeowHandlers) Receipt(evt events.Receipt) {
 	}
 }
 
+// Status (stories) views, dispatched as "statusview" events for each viewed status
+func (source *WhatsmeowHandlers) StatusView(evt events.Receipt) {
+	if evt.Type != types.ReceiptTypeRead && evt.Type != types.ReceiptTypePlayed {
+		return
+	}
+
+	if source.WAHandlers == nil || source.WAHandlers.IsInterfaceNil() || !source.HandleReadReceipts() {
+		return
+	}
+
+	for _, id := range evt.MessageIDs {
+		message := &whatsapp.WhatsappMessage{Content: evt}
+		message.Id = "statusview"
+		message.Timestamp = evt.Timestamp
+		message.Type = whatsapp.SystemMessageType
+
+		// viewer of the status
+		message.Chat = whatsapp.WhatsappChat{Id: fmt.Sprint(evt.Sender.User, "@", evt.Sender.Server)}
+		message.Chat.Title = GetChatTitle(source.Client, evt.Sender.ToNonAD())
+
+		// viewed status message id
+		message.Text = id
+		message.InReply = id
+
+		go source.WAHandlers.Receipt(message)
+	}
+}
+
+//#endregion
+
+//#region EVENT PRESENCE
+
+// Contact presence, only for subscribed contacts
+func (source *WhatsmeowHandlers) Presence(evt events.Presence) {
+	if source.WAHandlers == nil || source.WAHandlers.IsInterfaceNil() {
+		return
+	}
+
+	message := &whatsapp.WhatsappMessage{Content: evt}
+	message.Id = source.Client.GenerateMessageID()
+	message.Type = whatsapp.PresenceMessageType
+	message.Timestamp = time.Now().Truncate(time.Second)
+	message.Chat = whatsapp.WhatsappChat{Id: fmt.Sprint(evt.From.User, "@", evt.From.Server)}
+
+	if evt.Unavailable {
+		message.Text = string(types.PresenceUnavailable)
+		if !evt.LastSeen.IsZero() {
+			message.Timestamp = evt.LastSeen
+		}
+	} else {
+		message.Text = string(types.PresenceAvailable)
+	}
+
+	source.WAHandlers.Presence(message)
+}
+
+// Chat presence (typing, recording, paused) from an opened chat
+func (source *WhatsmeowHandlers) ChatPresence(evt events.ChatPresence) {
+	if source.WAHandlers == nil || source.WAHandlers.IsInterfaceNil() {
+		return
+	}
+
+	if evt.IsGroup && !source.HandleGroups() {
+		return
+	}
+
+	presence := whatsapp.WhatsappChatPresencePaused
+	if evt.State == types.ChatPresenceComposing {
+		presence = whatsapp.WhatsappChatPresenceTyping
+		if evt.Media == types.ChatPresenceMediaAudio {
+			presence = whatsapp.WhatsappChatPresenceRecording
+		}
+	}
+
+	message := &whatsapp.WhatsappMessage{Content: evt}
+	message.Id = source.Client.GenerateMessageID()
+	message.Type = whatsapp.PresenceMessageType
+	message.Timestamp = time.Now().Truncate(time.Second)
+	message.Chat = whatsapp.WhatsappChat{Id: fmt.Sprint(evt.Chat.User, "@", evt.Chat.Server)}
+	message.Text = presence.String()
+
+	if evt.IsGroup {
+		message.Participant = &whatsapp.WhatsappChat{Id: fmt.Sprint(evt.Sender.User, "@", evt.Sender.Server)}
+	}
+
+	source.WAHandlers.Presence(message)
+}
+
 //#endregion
 
 //#region HANDLE LOGGED OUT EVENT
@@ -35,6 +35,22 @@ func HandleKnowingMessages(handler *WhatsmeowHandlers, out *whatsapp.WhatsappMes
 		HandleEphemeralMessage(logentry, out, in.EphemeralMessage)
 	case in.ButtonsResponseMessage != nil:
 		HandleButtonsResponseMessage(logentry, out, in.ButtonsResponseMessage)
+	case in.ListResponseMessage != nil:
+		HandleListResponseMessage(logentry, out, in.ListResponseMessage)
+	case in.TemplateButtonReplyMessage != nil:
+		HandleTemplateButtonReplyMessage(logentry, out, in.TemplateButtonReplyMessage)
+	case in.ButtonsMessage != nil:
+		HandleButtonsMessage(logentry, out, in.ButtonsMessage)
+	case in.ListMessage != nil:
+		HandleListMessage(logentry, out, in.ListMessage)
+	case in.PollCreationMessage != nil:
+		HandlePollCreationMessage(logentry, out, in.PollCreationMessage)
+	case in.PollCreationMessageV2 != nil:
+		HandlePollCreationMessage(logentry, out, in.PollCreationMessageV2)
+	case in.PollCreationMessageV3 != nil:
+		HandlePollCreationMessage(logentry, out, in.PollCreationMessageV3)
+	case in.PollUpdateMessage != nil:
+		HandlePollUpdateMessage(logentry, out, in.PollUpdateMessage)
 	case in.LocationMessage != nil:
 		HandleLocationMessage(logentry, out, in.LocationMessage)
 	case in.LiveLocationMessage != nil:
@@ -195,6 +211,84 @@ func HandleButtonsResponseMessage(log *log.Entry, out *whatsapp.WhatsappMessage,
 	}
 }
 
+//#region HANDLING INTERACTIVE MESSAGES
+
+func HandleListResponseMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.ListResponseMessage) {
+	log.Debug("received a list response message !")
+	out.Type = whatsapp.TextMessageType
+	out.Text = in.GetSingleSelectReply().GetSelectedRowID()
+
+	info := in.ContextInfo
+	if info != nil {
+		out.ForwardingScore = info.GetForwardingScore()
+		out.InReply = info.GetStanzaID()
+	}
+}
+
+func HandleTemplateButtonReplyMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.TemplateButtonReplyMessage) {
+	log.Debug("received a template button reply message !")
+	out.Type = whatsapp.TextMessageType
+	out.Text = in.GetSelectedID()
+
+	info := in.ContextInfo
+	if info != nil {
+		out.ForwardingScore = info.GetForwardingScore()
+		out.InReply = info.GetStanzaID()
+	}
+}
+
+// buttons sent from another device
+func HandleButtonsMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.ButtonsMessage) {
+	log.Debug("received a buttons message !")
+	out.Type = whatsapp.ButtonsMessageType
+	out.Text = in.GetContentText()
+
+	buttons := &whatsapp.WhatsappButtons{Text: in.GetContentText(), Footer: in.GetFooterText()}
+	for _, button := range in.GetButtons() {
+		buttons.Buttons = append(buttons.Buttons, &whatsapp.WhatsappButton{Id: button.GetButtonID(), Text: button.GetButtonText().GetDisplayText()})
+	}
+	out.Buttons = buttons
+}
+
+// list sent from another device
+func HandleListMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.ListMessage) {
+	log.Debug("received a list message !")
+	out.Type = whatsapp.ListMessageType
+	out.Text = in.GetDescription()
+
+	list := &whatsapp.WhatsappList{Title: in.GetTitle(), Text: in.GetDescription(), Footer: in.GetFooterText(), Button: in.GetButtonText()}
+	for _, section := range in.GetSections() {
+		element := &whatsapp.WhatsappListSection{Title: section.GetTitle()}
+		for _, row := range section.GetRows() {
+			element.Rows = append(element.Rows, &whatsapp.WhatsappListRow{Id: row.GetRowID(), Title: row.GetTitle(), Description: row.GetDescription()})
+		}
+		list.Sections = append(list.Sections, element)
+	}
+	out.List = list
+}
+
+func HandlePollCreationMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.PollCreationMessage) {
+	log.Debug("received a poll message !")
+	out.Type = whatsapp.PollMessageType
+	out.Poll = ToWhatsappPoll(in)
+	out.Text = out.Poll.Question
+
+	info := in.ContextInfo
+	if info != nil {
+		out.ForwardingScore = info.GetForwardingScore()
+		out.InReply = info.GetStanzaID()
+	}
+}
+
+// votes are encrypted, decrypted later by handler with the original poll
+func HandlePollUpdateMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.PollUpdateMessage) {
+	log.Debug("received a poll vote message !")
+	out.Type = whatsapp.PollVoteMessageType
+	out.InReply = in.GetPollCreationMessageKey().GetID()
+}
+
+//#endregion
+
 func HandleImageMessage(logentry *log.Entry, out *whatsapp.WhatsappMessage, in *waE2E.ImageMessage) {
 	logentry.Debug("received an image message")
 	out.Type = whatsapp.ImageMessageType
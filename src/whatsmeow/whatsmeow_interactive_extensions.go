@@ -0,0 +1,91 @@
+package whatsmeow
+
+import (
+	"encoding/hex"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	whatsmeow "go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// Generates a quick reply buttons message from structured buttons
+func GenerateInteractiveButtonsMessage(source *whatsapp.WhatsappButtons) *waE2E.ButtonsMessage {
+	var buttons []*waE2E.ButtonsMessage_Button
+	for _, button := range source.Buttons {
+		buttonType := waE2E.ButtonsMessage_Button_RESPONSE
+		buttons = append(buttons, &waE2E.ButtonsMessage_Button{
+			ButtonID:   proto.String(button.Id),
+			ButtonText: &waE2E.ButtonsMessage_Button_ButtonText{DisplayText: proto.String(button.Text)},
+			Type:       &buttonType,
+		})
+	}
+
+	headerType := waE2E.ButtonsMessage_EMPTY
+	result := &waE2E.ButtonsMessage{HeaderType: &headerType, ContentText: proto.String(source.Text), Buttons: buttons}
+	if len(source.Footer) > 0 {
+		result.FooterText = proto.String(source.Footer)
+	}
+	return result
+}
+
+// Generates a single select list message from structured list
+func GenerateListMessage(source *whatsapp.WhatsappList) *waE2E.ListMessage {
+	var sections []*waE2E.ListMessage_Section
+	for _, section := range source.Sections {
+		var rows []*waE2E.ListMessage_Row
+		for _, row := range section.Rows {
+			element := &waE2E.ListMessage_Row{RowID: proto.String(row.Id), Title: proto.String(row.Title)}
+			if len(row.Description) > 0 {
+				element.Description = proto.String(row.Description)
+			}
+			rows = append(rows, element)
+		}
+
+		sections = append(sections, &waE2E.ListMessage_Section{Title: proto.String(section.Title), Rows: rows})
+	}
+
+	listType := waE2E.ListMessage_SINGLE_SELECT
+	result := &waE2E.ListMessage{
+		Title:       proto.String(source.Title),
+		Description: proto.String(source.Text),
+		ButtonText:  proto.String(source.Button),
+		ListType:    &listType,
+		Sections:    sections,
+	}
+
+	if len(source.Footer) > 0 {
+		result.FooterText = proto.String(source.Footer)
+	}
+	return result
+}
+
+// Converts a received poll creation (any version) to whatsapp poll
+func ToWhatsappPoll(in *waE2E.PollCreationMessage) *whatsapp.WhatsappPoll {
+	poll := &whatsapp.WhatsappPoll{
+		Question:   in.GetName(),
+		Selectable: uint(in.GetSelectableOptionsCount()),
+	}
+
+	for _, option := range in.GetOptions() {
+		poll.Options = append(poll.Options, option.GetOptionName())
+	}
+	return poll
+}
+
+// Resolves selected option hashes to option names, unknown hashes are returned as hex strings
+func GetPollSelectedOptions(options []string, hashes [][]byte) (selected []string) {
+	known := make(map[string]string, len(options))
+	for index, hash := range whatsmeow.HashPollOptions(options) {
+		known[string(hash)] = options[index]
+	}
+
+	for _, hash := range hashes {
+		if option, ok := known[string(hash)]; ok {
+			selected = append(selected, option)
+		} else {
+			selected = append(selected, hex.EncodeToString(hash))
+		}
+	}
+	return
+}
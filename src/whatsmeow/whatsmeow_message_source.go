@@ -0,0 +1,76 @@
+package whatsmeow
+
+import (
+	"encoding/json"
+
+	whatsapp "github.com/nocodeleaks/quepasa/whatsapp"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	types "go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+<summary>
+
+	Serializes the original whatsmeow message (content and info) to bytes, used by durable message stores
+	Returns nil slices for unknown types, they will not be restored
+
+</summary>
+*/
+func MarshalMessageSource(msg *whatsapp.WhatsappMessage) (content []byte, info []byte, err error) {
+	if msg == nil {
+		return
+	}
+
+	if waMsg, ok := msg.Content.(*waE2E.Message); ok && waMsg != nil {
+		content, err = proto.Marshal(waMsg)
+		if err != nil {
+			return
+		}
+	}
+
+	if msgInfo, ok := msg.InfoForHistory.(types.MessageInfo); ok {
+		info, err = json.Marshal(msgInfo)
+	}
+
+	return
+}
+
+/*
+<summary>
+
+	Restores the original whatsmeow message (content and info) from bytes generated by MarshalMessageSource
+	Marks the attachment as downloadable when content is available again
+
+</summary>
+*/
+func UnmarshalMessageSource(msg *whatsapp.WhatsappMessage, content []byte, info []byte) (err error) {
+	if msg == nil {
+		return
+	}
+
+	if len(content) > 0 {
+		waMsg := &waE2E.Message{}
+		err = proto.Unmarshal(content, waMsg)
+		if err != nil {
+			return
+		}
+
+		msg.Content = waMsg
+		if msg.Attachment != nil {
+			msg.Attachment.CanDownload = true
+		}
+	}
+
+	if len(info) > 0 {
+		var msgInfo types.MessageInfo
+		err = json.Unmarshal(info, &msgInfo)
+		if err != nil {
+			return
+		}
+
+		msg.InfoForHistory = msgInfo
+	}
+
+	return
+}
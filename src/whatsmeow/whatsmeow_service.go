@@ -240,6 +240,7 @@ func (source *WhatsmeowServiceModel) GetWhatsAppClient(options whatsapp.IWhatsap
 
 	deviceStore, err := source.GetOrCreateStore(wid)
 	if deviceStore != nil {
+		GetStatusContactStore(deviceStore)
 		client = whatsmeow.NewClient(deviceStore, clientLog)
 		client.AutoTrustIdentity = true
 		client.EnableAutoReconnect = options.GetReconnect()
@@ -0,0 +1,82 @@
+package whatsmeow
+
+import (
+	"sync"
+
+	store "go.mau.fi/whatsmeow/store"
+	types "go.mau.fi/whatsmeow/types"
+)
+
+/*
+<summary>
+
+	Contacts store wrapper, used to publish status (stories) to a custom recipient list
+	Whatsmeow builds the status audience from all stored contacts (unless privacy is whitelist),
+	so while publishing with recipients, GetAllContacts returns only those recipients
+
+</summary>
+*/
+type WhatsmeowStatusContactStore struct {
+	store.ContactStore
+
+	// one status publishing at a time, ordinary publishes should not use a restricted audience
+	publishing sync.Mutex
+
+	mutex      sync.RWMutex
+	recipients map[types.JID]types.ContactInfo
+}
+
+// Wraps device contacts store, only once
+func GetStatusContactStore(device *store.Device) *WhatsmeowStatusContactStore {
+	if wrapped, ok := device.Contacts.(*WhatsmeowStatusContactStore); ok {
+		return wrapped
+	}
+
+	wrapped := &WhatsmeowStatusContactStore{ContactStore: device.Contacts}
+	device.Contacts = wrapped
+	return wrapped
+}
+
+func (source *WhatsmeowStatusContactStore) GetAllContacts() (map[types.JID]types.ContactInfo, error) {
+	source.mutex.RLock()
+	recipients := source.recipients
+	source.mutex.RUnlock()
+
+	if recipients != nil {
+		return recipients, nil
+	}
+
+	return source.ContactStore.GetAllContacts()
+}
+
+// Runs publish with the default status audience, waiting for restricted ones
+func (source *WhatsmeowStatusContactStore) Publish(publish func() error) error {
+	source.publishing.Lock()
+	defer source.publishing.Unlock()
+
+	return publish()
+}
+
+// Runs publish with status audience restricted to recipients
+func (source *WhatsmeowStatusContactStore) WithRecipients(recipients []types.JID, publish func() error) error {
+	source.publishing.Lock()
+	defer source.publishing.Unlock()
+
+	contacts := make(map[types.JID]types.ContactInfo, len(recipients))
+	for _, jid := range recipients {
+		// whatsmeow skips contacts without full name
+		contacts[jid] = types.ContactInfo{Found: true, FullName: jid.User}
+	}
+
+	source.mutex.Lock()
+	source.recipients = contacts
+	source.mutex.Unlock()
+
+	defer func() {
+		source.mutex.Lock()
+		source.recipients = nil
+		source.mutex.Unlock()
+	}()
+
+	return publish()
+}